@@ -0,0 +1,77 @@
+//go:build windows
+
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gen2brain/malgo"
+)
+
+// DeviceInfo describes a WASAPI endpoint that can be captured.
+type DeviceInfo struct {
+	ID        string `json:"id"` // hex form of the malgo device ID
+	Name      string `json:"name"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// ListRenderDevices enumerates playback (render) endpoints available for loopback capture.
+func ListRenderDevices() ([]DeviceInfo, error) {
+	ctx, err := malgo.InitContext(nil, malgo.ContextConfig{}, func(message string) { _ = message })
+	if err != nil {
+		return nil, fmt.Errorf("init malgo context: %w", err)
+	}
+	defer func() {
+		_ = ctx.Uninit()
+		ctx.Free()
+	}()
+
+	infos, err := ctx.Devices(malgo.Playback)
+	if err != nil {
+		return nil, fmt.Errorf("enumerate render devices: %w", err)
+	}
+	devices := make([]DeviceInfo, 0, len(infos))
+	for i := range infos {
+		devices = append(devices, DeviceInfo{
+			ID:        infos[i].ID.String(),
+			Name:      infos[i].Name(),
+			IsDefault: infos[i].IsDefault != 0,
+		})
+	}
+	return devices, nil
+}
+
+// FindRenderDevice selects a device by exact ID or case-insensitive name
+// substring. A substring matching several devices is an error so the wrong
+// endpoint isn't captured silently.
+func FindRenderDevice(devices []DeviceInfo, query string) (DeviceInfo, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return DeviceInfo{}, errors.New("no device specified")
+	}
+	for _, d := range devices {
+		if d.ID == query {
+			return d, nil
+		}
+	}
+	var matches []DeviceInfo
+	for _, d := range devices {
+		if strings.Contains(strings.ToLower(d.Name), strings.ToLower(query)) {
+			matches = append(matches, d)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return DeviceInfo{}, fmt.Errorf("no render device matches %q", query)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, len(matches))
+		for i, m := range matches {
+			names[i] = m.Name
+		}
+		return DeviceInfo{}, fmt.Errorf("%q matches several render devices: %s", query, strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,54 @@
+package audio
+
+import (
+	"math"
+	"sync/atomic"
+
+	"blackbox/internal/dsp"
+)
+
+// MinDBFS is the floor reported for silence (the 16-bit noise floor), so
+// levels stay finite and JSON-encodable.
+const MinDBFS = -96.0
+
+// Levels is an input level reading in dBFS.
+type Levels struct {
+	RMSDBFS  float64 `json:"rms_dbfs"`  // RMS of the most recent buffer
+	PeakDBFS float64 `json:"peak_dbfs"` // highest peak since the previous reading
+	Clipped  bool    `json:"clipped"`   // a sample hit full scale since the previous reading
+}
+
+// levelMeter is updated from the device callback with atomics only, so it
+// never blocks the audio thread.
+type levelMeter struct {
+	rms     atomic.Uint64 // float64 bits
+	peak    atomic.Uint64 // float64 bits, max since last read
+	clipped atomic.Bool
+}
+
+func (m *levelMeter) update(b []byte) {
+	rms, peak, clipped := dsp.MeasureS16LE(b)
+	m.rms.Store(math.Float64bits(rms))
+	for {
+		old := m.peak.Load()
+		if peak <= math.Float64frombits(old) || m.peak.CompareAndSwap(old, math.Float64bits(peak)) {
+			break
+		}
+	}
+	if clipped {
+		m.clipped.Store(true)
+	}
+}
+
+// read returns the current levels and resets the peak hold and clip flag.
+func (m *levelMeter) read() Levels {
+	return Levels{
+		RMSDBFS:  toDBFS(math.Float64frombits(m.rms.Load())),
+		PeakDBFS: toDBFS(math.Float64frombits(m.peak.Swap(0))),
+		Clipped:  m.clipped.Swap(false),
+	}
+}
+
+func toDBFS(level float64) float64 {
+	return math.Max(dsp.DBFS(level), MinDBFS)
+}
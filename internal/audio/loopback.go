@@ -21,6 +21,12 @@ type Recorder struct {
 	dataCh    chan []byte
 	errCh     chan error
 	wg        sync.WaitGroup
+	latency   latencyTracker
+	buffers   bufferPool
+	levels    levelMeter
+
+	// stopCapture stops a non-malgo capture started by StartProcess.
+	stopCapture func()
 }
 
 // NewRecorder initializes a WASAPI loopback recorder with given buffer capacity.
@@ -46,6 +52,12 @@ func NewRecorder(bufferCallbacks int) (*Recorder, error) {
 // Start opens the default render device in loopback with the specified format.
 // sampleRate must match device mix rate (16k recommended for speech). channels=1 (mono) recommended, format S16.
 func (r *Recorder) Start(sampleRate uint32, channels uint32) error {
+	return r.StartOnDevice("", sampleRate, channels)
+}
+
+// StartOnDevice is like Start but captures the render device with the given ID
+// (as returned by ListRenderDevices). An empty ID uses the default device.
+func (r *Recorder) StartOnDevice(deviceID string, sampleRate uint32, channels uint32) error {
 	if r.ctx == nil {
 		return errors.New("context not initialized")
 	}
@@ -55,16 +67,24 @@ func (r *Recorder) Start(sampleRate uint32, channels uint32) error {
 	deviceConfig.Capture.Channels = uint32(channels)
 	deviceConfig.SampleRate = sampleRate
 	// Leave device IDs nil to use defaults (default render device for loopback)
+	if deviceID != "" {
+		id, err := r.renderDeviceID(deviceID)
+		if err != nil {
+			r.ctx.Uninit()
+			return err
+		}
+		// Loopback captures from a render device passed as the capture ID
+		deviceConfig.Capture.DeviceID = id.Pointer()
+	}
 
 	callbacks := malgo.DeviceCallbacks{
 		Data: func(pOutputSample, pInputSample []byte, frameCount uint32) {
 			// Copy buffer to avoid reuse by backend
-			b := make([]byte, len(pInputSample))
+			b := r.buffers.get(len(pInputSample))
 			copy(b, pInputSample)
-			select {
-			case r.dataCh <- b:
-			default:
-				// Drop if slow consumer; better to drop than block audio thread
+			r.levels.update(b)
+			if !r.latency.offer(r.dataCh, b, time.Now()) {
+				r.buffers.put(b)
 			}
 		},
 		Stop: func() {
@@ -92,15 +112,47 @@ func (r *Recorder) Start(sampleRate uint32, channels uint32) error {
 
 var ioClosed = errors.New("device stopped")
 
+// renderDeviceID returns the malgo ID of the render device whose ID string is id.
+func (r *Recorder) renderDeviceID(id string) (*malgo.DeviceID, error) {
+	infos, err := r.ctx.Devices(malgo.Playback)
+	if err != nil {
+		return nil, fmt.Errorf("enumerate render devices: %w", err)
+	}
+	for i := range infos {
+		if infos[i].ID.String() == id {
+			return &infos[i].ID, nil
+		}
+	}
+	return nil, fmt.Errorf("render device %s not found", id)
+}
+
 // Data returns the channel of PCM S16LE interleaved frames.
 func (r *Recorder) Data() <-chan []byte { return r.dataCh }
 
+// MarkWritten reports that the oldest buffer received from Data has been written.
+// It feeds the latency figures returned by CaptureMetrics.
+func (r *Recorder) MarkWritten() { r.latency.written(time.Now()) }
+
+// CaptureMetrics returns callback-to-write latency and drop counts so far.
+func (r *Recorder) CaptureMetrics() CaptureMetrics { return r.latency.snapshot() }
+
+// Levels returns the input level, with peak and clipping held since the previous call.
+func (r *Recorder) Levels() Levels { return r.levels.read() }
+
+// Release returns a buffer received from Data for reuse by the capture callback.
+// b must not be used after Release, including by anything it was handed to.
+func (r *Recorder) Release(b []byte) { r.buffers.put(b) }
+
 // Errors emits terminal errors or device stop events.
 func (r *Recorder) Errors() <-chan error { return r.errCh }
 
 // Stop stops the device and closes channels after draining briefly.
 func (r *Recorder) Stop() {
 	r.onceClose.Do(func() {
+		if r.stopCapture != nil {
+			r.stopCapture()
+			r.stopCapture = nil
+		}
 		if r.device != nil {
 			_ = r.device.Stop()
 			r.device.Uninit()
@@ -116,6 +168,7 @@ func (r *Recorder) Stop() {
 
 // RunUntil runs the recorder, forwarding samples into the provided sink function.
 // It returns when context is done, an error occurs, or device stops.
+// Buffers are recycled once sink returns, so sink must not retain them.
 func (r *Recorder) RunUntil(ctx context.Context, sink func([]byte) error) error {
 	for {
 		select {
@@ -133,6 +186,8 @@ func (r *Recorder) RunUntil(ctx context.Context, sink func([]byte) error) error
 			if err := sink(b); err != nil {
 				return err
 			}
+			r.MarkWritten()
+			r.Release(b)
 		}
 	}
 }
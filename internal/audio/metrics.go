@@ -0,0 +1,84 @@
+package audio
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// CaptureMetrics summarises the delay between a device callback delivering a
+// buffer and the consumer reporting it written. Useful for buffer-size tuning.
+type CaptureMetrics struct {
+	Buffers      int64   `json:"buffers"`        // buffers written
+	Dropped      int64   `json:"dropped"`        // buffers dropped because the queue was full
+	AvgLatencyMs float64 `json:"avg_latency_ms"` // mean callback-to-write latency
+	MaxLatencyMs float64 `json:"max_latency_ms"` // worst callback-to-write latency
+}
+
+// latencySlots bounds the buffers that can be queued or held unwritten at
+// once; it must exceed the data channel capacity plus the buffer in hand.
+const latencySlots = 256
+
+// trackerEpoch anchors stored timestamps so they keep the monotonic clock.
+var trackerEpoch = time.Now()
+
+// latencyTracker keeps callback timestamps for queued buffers in a ring
+// indexed by send order, matching the order buffers are read from the data
+// channel. It is updated with atomics only, so it never blocks the audio
+// thread; offer has a single caller (the device callback) and written a
+// single caller (the writer loop).
+type latencyTracker struct {
+	pending [latencySlots]atomic.Int64 // callback time, ns since trackerEpoch
+	sent    atomic.Int64               // buffers queued
+	done    atomic.Int64               // buffers reported written
+	dropped atomic.Int64
+	total   atomic.Int64 // ns
+	max     atomic.Int64 // ns
+}
+
+// offer attempts a non-blocking send of b on ch, recording the callback time
+// on success or counting a drop otherwise. The timestamp is stored before the
+// send, so it is visible to whoever receives b. Reports whether b was queued.
+func (t *latencyTracker) offer(ch chan<- []byte, b []byte, at time.Time) bool {
+	seq := t.sent.Load()
+	t.pending[seq%latencySlots].Store(int64(at.Sub(trackerEpoch)))
+	t.sent.Store(seq + 1)
+	select {
+	case ch <- b:
+		return true
+	default:
+		// Drop if slow consumer; better to drop than block audio thread
+		t.sent.Store(seq)
+		t.dropped.Add(1)
+		return false
+	}
+}
+
+// written records that the oldest queued buffer has been consumed at now.
+func (t *latencyTracker) written(now time.Time) {
+	seq := t.done.Load()
+	if seq >= t.sent.Load() {
+		return
+	}
+	d := int64(now.Sub(trackerEpoch)) - t.pending[seq%latencySlots].Load()
+	t.done.Store(seq + 1)
+	t.total.Add(d)
+	for {
+		old := t.max.Load()
+		if d <= old || t.max.CompareAndSwap(old, d) {
+			break
+		}
+	}
+}
+
+func (t *latencyTracker) snapshot() CaptureMetrics {
+	count := t.done.Load()
+	m := CaptureMetrics{
+		Buffers:      count,
+		Dropped:      t.dropped.Load(),
+		MaxLatencyMs: float64(t.max.Load()) / float64(time.Millisecond),
+	}
+	if count > 0 {
+		m.AvgLatencyMs = float64(t.total.Load()) / float64(count) / float64(time.Millisecond)
+	}
+	return m
+}
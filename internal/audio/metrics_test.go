@@ -0,0 +1,77 @@
+package audio
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	var tr latencyTracker
+	ch := make(chan []byte, 2)
+	t0 := time.Now()
+	ms := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Millisecond) }
+
+	// Two queued at 0 and 5 ms; the third finds the queue full
+	for i, at := range []time.Time{ms(0), ms(5), ms(6)} {
+		if queued := tr.offer(ch, []byte{byte(i)}, at); queued != (i < 2) {
+			t.Fatalf("offer %d queued = %v", i, queued)
+		}
+	}
+	// Written at 10 and 25 ms: latencies 10 and 20
+	for _, at := range []time.Time{ms(10), ms(25)} {
+		<-ch
+		tr.written(at)
+	}
+	// A written report with nothing queued is ignored
+	tr.written(ms(100))
+
+	want := CaptureMetrics{Buffers: 2, Dropped: 1, AvgLatencyMs: 15, MaxLatencyMs: 20}
+	if got := tr.snapshot(); got != want {
+		t.Errorf("snapshot = %+v, want %+v", got, want)
+	}
+}
+
+// TestLatencyTrackerWraps runs more buffers than the ring holds through the
+// tracker to check timestamps stay paired with their buffers.
+func TestLatencyTrackerWraps(t *testing.T) {
+	var tr latencyTracker
+	ch := make(chan []byte, 8)
+	t0 := time.Now()
+	const n = latencySlots*3 + 5
+	for i := range n {
+		at := t0.Add(time.Duration(i) * time.Millisecond)
+		if !tr.offer(ch, nil, at) {
+			t.Fatalf("offer %d dropped", i)
+		}
+		<-ch
+		tr.written(at.Add(time.Duration(i%4) * time.Millisecond))
+	}
+	m := tr.snapshot()
+	if m.Buffers != n || m.Dropped != 0 || m.MaxLatencyMs != 3 {
+		t.Errorf("snapshot = %+v, want %d buffers and a 3 ms max", m, n)
+	}
+	if m.AvgLatencyMs < 1.4 || m.AvgLatencyMs > 1.6 {
+		t.Errorf("avg latency = %.3f ms, want about 1.5", m.AvgLatencyMs)
+	}
+}
+
+// TestLatencyTrackerConcurrent feeds the tracker from a producer goroutine
+// while a consumer drains it; run with -race.
+func TestLatencyTrackerConcurrent(t *testing.T) {
+	var tr latencyTracker
+	ch := make(chan []byte, 8)
+	const n = 2000
+	go func() {
+		for range n {
+			tr.offer(ch, nil, time.Now())
+		}
+		close(ch)
+	}()
+	for range ch {
+		tr.written(time.Now())
+		_ = tr.snapshot()
+	}
+	if m := tr.snapshot(); m.Buffers+m.Dropped != n || m.MaxLatencyMs < 0 {
+		t.Errorf("snapshot = %+v, want %d buffers written or dropped", m, n)
+	}
+}
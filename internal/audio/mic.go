@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gen2brain/malgo"
 )
@@ -13,9 +14,12 @@ import (
 // MicRecorder captures default microphone audio (WASAPI capture).
 // It emits raw PCM S16LE frames (interleaved) through a channel.
 type MicRecorder struct {
-	ctx    *malgo.AllocatedContext
-	device *malgo.Device
-	dataCh chan []byte
+	ctx     *malgo.AllocatedContext
+	device  *malgo.Device
+	dataCh  chan []byte
+	latency latencyTracker
+	buffers bufferPool
+	levels  levelMeter
 }
 
 func NewMicRecorder(bufferCallbacks int) (*MicRecorder, error) {
@@ -42,11 +46,11 @@ func (r *MicRecorder) Start(sampleRate uint32, channels uint32) error {
 
 	callbacks := malgo.DeviceCallbacks{
 		Data: func(pOutputSample, pInputSample []byte, frameCount uint32) {
-			b := make([]byte, len(pInputSample))
+			b := r.buffers.get(len(pInputSample))
 			copy(b, pInputSample)
-			select {
-			case r.dataCh <- b:
-			default:
+			r.levels.update(b)
+			if !r.latency.offer(r.dataCh, b, time.Now()) {
+				r.buffers.put(b)
 			}
 		},
 	}
@@ -67,6 +71,19 @@ func (r *MicRecorder) Start(sampleRate uint32, channels uint32) error {
 
 func (r *MicRecorder) Data() <-chan []byte { return r.dataCh }
 
+// MarkWritten reports that the oldest buffer received from Data has been written.
+func (r *MicRecorder) MarkWritten() { r.latency.written(time.Now()) }
+
+// CaptureMetrics returns callback-to-write latency and drop counts so far.
+func (r *MicRecorder) CaptureMetrics() CaptureMetrics { return r.latency.snapshot() }
+
+// Levels returns the input level, with peak and clipping held since the previous call.
+func (r *MicRecorder) Levels() Levels { return r.levels.read() }
+
+// Release returns a buffer received from Data for reuse by the capture callback.
+// b must not be used after Release, including by anything it was handed to.
+func (r *MicRecorder) Release(b []byte) { r.buffers.put(b) }
+
 func (r *MicRecorder) Stop() {
 	if r.device != nil {
 		_ = r.device.Stop()
@@ -95,6 +112,8 @@ func (r *MicRecorder) RunUntil(ctx context.Context, sink func([]byte) error) err
 			if err := sink(b); err != nil {
 				return err
 			}
+			r.MarkWritten()
+			r.Release(b)
 		}
 	}
 }
@@ -0,0 +1,42 @@
+package audio
+
+import "sync"
+
+// bufferPool recycles capture buffers between the device callback and the
+// consumer. Buffers only return to the pool via Release, so a buffer still
+// queued in dataCh (or being written) is never handed out again. Consumers
+// that don't call Release simply leave buffers to the garbage collector.
+type bufferPool struct {
+	pool sync.Pool
+	// holders recycles the *[]byte boxes that carry buffers through pool,
+	// so put doesn't allocate a new slice header on every Release.
+	holders sync.Pool
+}
+
+// get returns a buffer of length n, reusing a pooled one when large enough.
+func (p *bufferPool) get(n int) []byte {
+	v, ok := p.pool.Get().(*[]byte)
+	if !ok {
+		return make([]byte, n)
+	}
+	b := *v
+	*v = nil
+	p.holders.Put(v)
+	if cap(b) < n {
+		return make([]byte, n)
+	}
+	return b[:n]
+}
+
+// put returns b to the pool. b must not be used afterwards.
+func (p *bufferPool) put(b []byte) {
+	if cap(b) == 0 {
+		return
+	}
+	v, ok := p.holders.Get().(*[]byte)
+	if !ok {
+		v = new([]byte)
+	}
+	*v = b
+	p.pool.Put(v)
+}
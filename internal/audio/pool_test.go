@@ -0,0 +1,62 @@
+package audio
+
+import "testing"
+
+func TestBufferPoolGet(t *testing.T) {
+	tests := []struct {
+		name   string
+		pooled int // capacity returned to the pool first, 0 for none
+		n      int
+		reused bool
+	}{
+		{"empty pool", 0, 512, false},
+		{"large enough", 1024, 512, true},
+		{"exact size", 512, 512, true},
+		{"too small", 256, 512, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p bufferPool
+			var pooled []byte
+			if tt.pooled > 0 {
+				pooled = make([]byte, tt.pooled)
+				p.put(pooled)
+			}
+			b := p.get(tt.n)
+			if len(b) != tt.n {
+				t.Fatalf("len = %d, want %d", len(b), tt.n)
+			}
+			// sync.Pool may drop entries, so only a reuse we didn't expect fails
+			if reused := pooled != nil && &b[0] == &pooled[0]; reused && !tt.reused {
+				t.Errorf("reused a %d-byte buffer for a %d-byte request", tt.pooled, tt.n)
+			}
+		})
+	}
+}
+
+// captureBytes matches a 10 ms stereo float32 callback at 48 kHz.
+const captureBytes = 480 * 2 * 4
+
+// BenchmarkCaptureAlloc is the per-callback allocation the pool replaces.
+func BenchmarkCaptureAlloc(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		buf := make([]byte, captureBytes)
+		sink = buf
+	}
+}
+
+// BenchmarkCapturePool should report 0 allocs/op once the pool is warm.
+func BenchmarkCapturePool(b *testing.B) {
+	var p bufferPool
+	p.put(make([]byte, captureBytes))
+	b.ReportAllocs()
+	for b.Loop() {
+		buf := p.get(captureBytes)
+		sink = buf
+		p.put(buf)
+	}
+}
+
+// sink keeps benchmark buffers from being optimised away.
+var sink []byte
@@ -0,0 +1,94 @@
+//go:build windows
+
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// ErrProcessLoopbackUnsupported is returned by StartProcess when the binary was
+// built without the processloopback tag or Windows doesn't support it (pre-2004).
+var ErrProcessLoopbackUnsupported = errors.New("per-process loopback capture is not supported")
+
+// Process identifies a running process that can be targeted for loopback capture.
+type Process struct {
+	PID  uint32 `json:"pid"`
+	Name string `json:"name"`
+}
+
+// ListProcesses returns running processes sorted by name, then PID.
+func ListProcesses() ([]Process, error) {
+	snap, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("process snapshot: %w", err)
+	}
+	defer syscall.CloseHandle(snap)
+
+	var entry syscall.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	if err := syscall.Process32First(snap, &entry); err != nil {
+		return nil, fmt.Errorf("first process: %w", err)
+	}
+	var procs []Process
+	for {
+		if entry.ProcessID != 0 {
+			procs = append(procs, Process{
+				PID:  entry.ProcessID,
+				Name: syscall.UTF16ToString(entry.ExeFile[:]),
+			})
+		}
+		if err := syscall.Process32Next(snap, &entry); err != nil {
+			break // ERROR_NO_MORE_FILES
+		}
+	}
+	sortProcesses(procs)
+	return procs, nil
+}
+
+func sortProcesses(procs []Process) {
+	sort.Slice(procs, func(i, j int) bool {
+		ni, nj := strings.ToLower(procs[i].Name), strings.ToLower(procs[j].Name)
+		if ni != nj {
+			return ni < nj
+		}
+		return procs[i].PID < procs[j].PID
+	})
+}
+
+// FindProcess selects the capture target from procs. target is either a PID
+// or an executable name (case-insensitive, ".exe" optional). When several
+// processes share the name, the lowest PID wins: process loopback includes
+// the whole process tree, so that is usually the parent.
+func FindProcess(procs []Process, target string) (Process, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return Process{}, errors.New("no process specified")
+	}
+	if pid, err := strconv.ParseUint(target, 10, 32); err == nil {
+		for _, p := range procs {
+			if p.PID == uint32(pid) {
+				return p, nil
+			}
+		}
+		return Process{}, fmt.Errorf("no process with PID %d", pid)
+	}
+
+	want := strings.TrimSuffix(strings.ToLower(target), ".exe")
+	var found *Process
+	for i := range procs {
+		name := strings.TrimSuffix(strings.ToLower(procs[i].Name), ".exe")
+		if name == want && (found == nil || procs[i].PID < found.PID) {
+			found = &procs[i]
+		}
+	}
+	if found == nil {
+		return Process{}, fmt.Errorf("process %q is not running", target)
+	}
+	return *found, nil
+}
@@ -0,0 +1,383 @@
+//go:build windows && processloopback && (amd64 || arm64)
+
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// ProcessLoopbackSupported reports whether this build can capture a single
+// process's audio. Build with -tags processloopback (64-bit only) to enable it.
+const ProcessLoopbackSupported = true
+
+// Per-process loopback is not exposed by miniaudio's Go bindings, so it talks
+// to WASAPI directly: ActivateAudioInterfaceAsync on the process loopback
+// virtual device (Windows 10 2004+), then an event-driven IAudioCaptureClient.
+// 64-bit only: IAudioClient::Initialize takes REFERENCE_TIME in a single register.
+
+var (
+	modmmdevapi = syscall.NewLazyDLL("mmdevapi.dll")
+	modole32    = syscall.NewLazyDLL("ole32.dll")
+	modkernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	procActivateAudioInterfaceAsync = modmmdevapi.NewProc("ActivateAudioInterfaceAsync")
+	procCoInitializeEx              = modole32.NewProc("CoInitializeEx")
+	procCoUninitialize              = modole32.NewProc("CoUninitialize")
+	procCreateEventW                = modkernel32.NewProc("CreateEventW")
+)
+
+type guid struct {
+	Data1 uint32
+	Data2 uint16
+	Data3 uint16
+	Data4 [8]byte
+}
+
+var (
+	iidIUnknown          = guid{0x00000000, 0x0000, 0x0000, [8]byte{0xC0, 0, 0, 0, 0, 0, 0, 0x46}}
+	iidIAgileObject      = guid{0x94EA2B94, 0xE9CC, 0x49E0, [8]byte{0xC0, 0xFF, 0xEE, 0x64, 0xCA, 0x8F, 0x5B, 0x90}}
+	iidIAudioClient      = guid{0x1CB9AD4C, 0xDBFA, 0x4C32, [8]byte{0xB1, 0x78, 0xC2, 0xF5, 0x68, 0xA7, 0x03, 0xB2}}
+	iidIAudioCapture     = guid{0xC8ADBD64, 0xE71E, 0x48A0, [8]byte{0xA4, 0xDE, 0x18, 0x5C, 0x39, 0x5C, 0xD3, 0x17}}
+	iidActivateCompleted = guid{0x41D949AB, 0x9862, 0x444A, [8]byte{0x80, 0xF6, 0xC2, 0x61, 0x33, 0x4D, 0xA5, 0xEB}}
+)
+
+const (
+	processLoopbackDevice = `VAD\Process_Loopback`
+
+	coinitMultithreaded = 0x0
+	vtBlob              = 65
+
+	activationTypeProcessLoopback = 1
+	loopbackModeIncludeTree       = 0
+
+	audclntStreamflagsLoopback       = 0x00020000
+	audclntStreamflagsEventCallback  = 0x00040000
+	audclntStreamflagsSrcDefaultQual = 0x08000000
+	audclntStreamflagsAutoConvertPCM = 0x80000000
+	audclntBufferflagsSilent         = 0x2
+	waveFormatPCM                    = 1
+	processBufferDuration            = 200 * time.Millisecond
+	processActivateTimeout           = 5 * time.Second
+)
+
+const eNoInterface uintptr = 0x80004002
+
+// COM vtable indices
+const (
+	methodQueryInterface = 0
+	methodRelease        = 2
+
+	activateOpGetActivateResult = 3
+
+	audioClientInitialize     = 3
+	audioClientStart          = 10
+	audioClientStop           = 11
+	audioClientSetEventHandle = 13
+	audioClientGetService     = 14
+
+	captureClientGetBuffer         = 3
+	captureClientReleaseBuffer     = 4
+	captureClientGetNextPacketSize = 5
+)
+
+// comObject is a COM interface pointer; only vtable entries that exist are indexed.
+type comObject struct {
+	vtbl *[16]uintptr
+}
+
+func (o *comObject) call(method int, args ...uintptr) uintptr {
+	r, _, _ := syscall.SyscallN(o.vtbl[method], append([]uintptr{uintptr(unsafe.Pointer(o))}, args...)...)
+	return r
+}
+
+func (o *comObject) release() {
+	if o != nil {
+		o.call(methodRelease)
+	}
+}
+
+func failed(hr uintptr) bool { return int32(hr) < 0 }
+
+type audioClientActivationParams struct {
+	ActivationType  uint32
+	TargetProcessID uint32
+	LoopbackMode    uint32
+}
+
+type propVariantBlob struct {
+	vt        uint16
+	reserved1 uint16
+	reserved2 uint16
+	reserved3 uint16
+	cbSize    uint32
+	pBlobData uintptr
+}
+
+type waveFormatEx struct {
+	FormatTag      uint16
+	Channels       uint16
+	SamplesPerSec  uint32
+	AvgBytesPerSec uint32
+	BlockAlign     uint16
+	BitsPerSample  uint16
+	Size           uint16
+}
+
+// completionHandler implements IActivateAudioInterfaceCompletionHandler (and
+// IAgileObject, which activation requires) as a Go-allocated COM object.
+type completionHandler struct {
+	vtbl *[4]uintptr
+	refs int32
+	done chan struct{}
+	hr   uintptr
+	unk  *comObject
+}
+
+var (
+	handlerVtblOnce sync.Once
+	handlerVtbl     [4]uintptr
+)
+
+func newCompletionHandler() *completionHandler {
+	handlerVtblOnce.Do(func() {
+		handlerVtbl = [4]uintptr{
+			syscall.NewCallback(handlerQueryInterface),
+			syscall.NewCallback(handlerAddRef),
+			syscall.NewCallback(handlerRelease),
+			syscall.NewCallback(handlerActivateCompleted),
+		}
+	})
+	return &completionHandler{vtbl: &handlerVtbl, refs: 1, done: make(chan struct{})}
+}
+
+func handlerQueryInterface(this *completionHandler, riid *guid, ppv **completionHandler) uintptr {
+	switch *riid {
+	case iidIUnknown, iidIAgileObject, iidActivateCompleted:
+		atomic.AddInt32(&this.refs, 1)
+		*ppv = this
+		return 0
+	}
+	*ppv = nil
+	return eNoInterface
+}
+
+func handlerAddRef(this *completionHandler) uintptr {
+	return uintptr(atomic.AddInt32(&this.refs, 1))
+}
+
+// handlerRelease only counts; the Go object is kept alive by activateProcessClient.
+func handlerRelease(this *completionHandler) uintptr {
+	return uintptr(atomic.AddInt32(&this.refs, -1))
+}
+
+func handlerActivateCompleted(this *completionHandler, op *comObject) uintptr {
+	var hr uintptr
+	var unk *comObject
+	if r := op.call(activateOpGetActivateResult, uintptr(unsafe.Pointer(&hr)), uintptr(unsafe.Pointer(&unk))); failed(r) {
+		hr = r
+	}
+	this.hr = hr
+	this.unk = unk
+	close(this.done)
+	return 0
+}
+
+// activateProcessClient returns an IAudioClient capturing pid's process tree.
+func activateProcessClient(pid uint32) (*comObject, error) {
+	if err := procActivateAudioInterfaceAsync.Find(); err != nil {
+		return nil, ErrProcessLoopbackUnsupported
+	}
+	params := audioClientActivationParams{
+		ActivationType:  activationTypeProcessLoopback,
+		TargetProcessID: pid,
+		LoopbackMode:    loopbackModeIncludeTree,
+	}
+	pv := propVariantBlob{
+		vt:        vtBlob,
+		cbSize:    uint32(unsafe.Sizeof(params)),
+		pBlobData: uintptr(unsafe.Pointer(&params)),
+	}
+	device, err := syscall.UTF16PtrFromString(processLoopbackDevice)
+	if err != nil {
+		return nil, err
+	}
+	handler := newCompletionHandler()
+
+	var op *comObject
+	hr, _, _ := procActivateAudioInterfaceAsync.Call(
+		uintptr(unsafe.Pointer(device)),
+		uintptr(unsafe.Pointer(&iidIAudioClient)),
+		uintptr(unsafe.Pointer(&pv)),
+		uintptr(unsafe.Pointer(handler)),
+		uintptr(unsafe.Pointer(&op)),
+	)
+	if failed(hr) {
+		return nil, fmt.Errorf("activate process loopback: hresult 0x%08x", uint32(hr))
+	}
+	defer op.release()
+
+	select {
+	case <-handler.done:
+	case <-time.After(processActivateTimeout):
+		// Windows may still call the handler, so keep everything alive until it does
+		go func() {
+			<-handler.done
+			runtime.KeepAlive(&params)
+			runtime.KeepAlive(&pv)
+			handler.unk.release()
+		}()
+		return nil, errors.New("activate process loopback: timed out")
+	}
+	// Windows holds these until activation completes
+	runtime.KeepAlive(handler)
+	runtime.KeepAlive(&params)
+	runtime.KeepAlive(&pv)
+
+	if failed(handler.hr) || handler.unk == nil {
+		return nil, fmt.Errorf("activate process loopback: hresult 0x%08x", uint32(handler.hr))
+	}
+	var client *comObject
+	hr = handler.unk.call(methodQueryInterface, uintptr(unsafe.Pointer(&iidIAudioClient)), uintptr(unsafe.Pointer(&client)))
+	handler.unk.release()
+	if failed(hr) {
+		return nil, fmt.Errorf("query IAudioClient: hresult 0x%08x", uint32(hr))
+	}
+	return client, nil
+}
+
+// StartProcess captures audio rendered by process pid (and its children) in
+// the given format. Data is delivered through Data like Start. Requires
+// Windows 10 2004 or later; on failure callers should fall back to Start.
+func (r *Recorder) StartProcess(pid uint32, sampleRate uint32, channels uint32) error {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	started := make(chan error, 1)
+	go r.captureProcess(pid, sampleRate, channels, stop, done, started)
+	if err := <-started; err != nil {
+		<-done
+		return err
+	}
+	r.stopCapture = func() {
+		close(stop)
+		<-done
+	}
+	return nil
+}
+
+// captureProcess runs the WASAPI capture loop on a dedicated MTA thread.
+func (r *Recorder) captureProcess(pid, sampleRate, channels uint32, stop <-chan struct{}, done chan<- struct{}, started chan<- error) {
+	defer close(done)
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if hr, _, _ := procCoInitializeEx.Call(0, coinitMultithreaded); failed(hr) {
+		started <- fmt.Errorf("CoInitializeEx: hresult 0x%08x", uint32(hr))
+		return
+	}
+	defer procCoUninitialize.Call()
+
+	client, err := activateProcessClient(pid)
+	if err != nil {
+		started <- err
+		return
+	}
+	defer client.release()
+
+	blockAlign := uint16(channels * 2)
+	wfx := waveFormatEx{
+		FormatTag:      waveFormatPCM,
+		Channels:       uint16(channels),
+		SamplesPerSec:  sampleRate,
+		AvgBytesPerSec: sampleRate * uint32(blockAlign),
+		BlockAlign:     blockAlign,
+		BitsPerSample:  16,
+	}
+	flags := uintptr(audclntStreamflagsLoopback | audclntStreamflagsEventCallback |
+		audclntStreamflagsAutoConvertPCM | audclntStreamflagsSrcDefaultQual)
+	duration := uintptr(processBufferDuration / 100) // REFERENCE_TIME is in 100ns units
+	if hr := client.call(audioClientInitialize, 0, flags, duration, 0, uintptr(unsafe.Pointer(&wfx)), 0); failed(hr) {
+		started <- fmt.Errorf("initialize audio client: hresult 0x%08x", uint32(hr))
+		return
+	}
+
+	event, _, callErr := procCreateEventW.Call(0, 0, 0, 0)
+	if event == 0 {
+		started <- fmt.Errorf("create event: %w", callErr)
+		return
+	}
+	defer syscall.CloseHandle(syscall.Handle(event))
+	if hr := client.call(audioClientSetEventHandle, event); failed(hr) {
+		started <- fmt.Errorf("set event handle: hresult 0x%08x", uint32(hr))
+		return
+	}
+
+	var capture *comObject
+	if hr := client.call(audioClientGetService, uintptr(unsafe.Pointer(&iidIAudioCapture)), uintptr(unsafe.Pointer(&capture))); failed(hr) {
+		started <- fmt.Errorf("get capture client: hresult 0x%08x", uint32(hr))
+		return
+	}
+	defer capture.release()
+
+	if hr := client.call(audioClientStart); failed(hr) {
+		started <- fmt.Errorf("start audio client: hresult 0x%08x", uint32(hr))
+		return
+	}
+	defer client.call(audioClientStop)
+	started <- nil
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if ev, _ := syscall.WaitForSingleObject(syscall.Handle(event), 100); ev != syscall.WAIT_OBJECT_0 {
+			continue
+		}
+		if err := r.drainCapture(capture, int(blockAlign)); err != nil {
+			select {
+			case r.errCh <- err:
+			default:
+			}
+			return
+		}
+	}
+}
+
+// drainCapture forwards every packet currently queued in the capture client.
+func (r *Recorder) drainCapture(capture *comObject, blockAlign int) error {
+	for {
+		var packet uint32
+		if hr := capture.call(captureClientGetNextPacketSize, uintptr(unsafe.Pointer(&packet))); failed(hr) {
+			return fmt.Errorf("get packet size: hresult 0x%08x", uint32(hr))
+		}
+		if packet == 0 {
+			return nil
+		}
+		var data *byte
+		var frames, flags uint32
+		if hr := capture.call(captureClientGetBuffer,
+			uintptr(unsafe.Pointer(&data)), uintptr(unsafe.Pointer(&frames)), uintptr(unsafe.Pointer(&flags)), 0, 0); failed(hr) {
+			return fmt.Errorf("get buffer: hresult 0x%08x", uint32(hr))
+		}
+		n := int(frames) * blockAlign
+		b := r.buffers.get(n)
+		if flags&audclntBufferflagsSilent != 0 || data == nil {
+			clear(b)
+		} else {
+			copy(b, unsafe.Slice(data, n))
+		}
+		capture.call(captureClientReleaseBuffer, uintptr(frames))
+		r.levels.update(b)
+		if n > 0 && !r.latency.offer(r.dataCh, b, time.Now()) {
+			r.buffers.put(b)
+		}
+	}
+}
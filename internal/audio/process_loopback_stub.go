@@ -0,0 +1,13 @@
+//go:build windows && !(processloopback && (amd64 || arm64))
+
+package audio
+
+// ProcessLoopbackSupported reports whether this build can capture a single
+// process's audio. Build with -tags processloopback (64-bit only) to enable it.
+const ProcessLoopbackSupported = false
+
+// StartProcess always fails in builds without the processloopback tag;
+// callers should fall back to Start.
+func (r *Recorder) StartProcess(pid uint32, sampleRate uint32, channels uint32) error {
+	return ErrProcessLoopbackUnsupported
+}
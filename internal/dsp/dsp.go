@@ -0,0 +1,105 @@
+// Package dsp contains small signal-processing helpers for PCM S16LE audio.
+package dsp
+
+import "math"
+
+// FullScale is the magnitude of the largest S16 sample.
+const FullScale = 32768.0
+
+// DecodeS16LE decodes little-endian 16-bit PCM into samples, reusing dst when it has capacity.
+// A trailing odd byte is ignored.
+func DecodeS16LE(dst []int16, b []byte) []int16 {
+	n := len(b) / 2
+	if cap(dst) < n {
+		dst = make([]int16, n)
+	}
+	dst = dst[:n]
+	for i := 0; i < n; i++ {
+		dst[i] = int16(uint16(b[2*i]) | uint16(b[2*i+1])<<8)
+	}
+	return dst
+}
+
+// EncodeS16LE encodes samples as little-endian 16-bit PCM, reusing dst when it has capacity.
+func EncodeS16LE(dst []byte, samples []int16) []byte {
+	n := len(samples) * 2
+	if cap(dst) < n {
+		dst = make([]byte, n)
+	}
+	dst = dst[:n]
+	for i, s := range samples {
+		dst[2*i] = byte(uint16(s))
+		dst[2*i+1] = byte(uint16(s) >> 8)
+	}
+	return dst
+}
+
+// RMS returns the root-mean-square level of samples, normalised to [0, 1].
+func RMS(samples []int16) float64 {
+	return ChannelRMS(samples, 1, 0)
+}
+
+// ChannelRMS returns the normalised RMS level of channel ch in interleaved samples.
+func ChannelRMS(samples []int16, channels, ch int) float64 {
+	if channels < 1 || ch < 0 || ch >= channels {
+		return 0
+	}
+	var sum float64
+	var n int
+	for i := ch; i < len(samples); i += channels {
+		v := float64(samples[i]) / FullScale
+		sum += v * v
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return math.Sqrt(sum / float64(n))
+}
+
+// DBFS converts a normalised amplitude to decibels relative to full scale.
+// Zero maps to -Inf.
+func DBFS(level float64) float64 {
+	if level <= 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(level)
+}
+
+// MeasureS16LE returns the normalised RMS and peak levels of little-endian
+// 16-bit PCM in b without allocating, and whether any sample hit full scale
+// (±32767 or -32768). A trailing odd byte is ignored.
+func MeasureS16LE(b []byte) (rms, peak float64, clipped bool) {
+	n := len(b) / 2
+	if n == 0 {
+		return 0, 0, false
+	}
+	var sum float64
+	var maxAbs int32
+	for i := 0; i < n; i++ {
+		s := int32(int16(uint16(b[2*i]) | uint16(b[2*i+1])<<8))
+		if s < 0 {
+			s = -s
+		}
+		if s > maxAbs {
+			maxAbs = s
+		}
+		v := float64(s) / FullScale
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(n)), float64(maxAbs) / FullScale, maxAbs >= 32767
+}
+
+// Gain scales samples in place by g, clamping to the S16 range.
+func Gain(samples []int16, g float64) {
+	for i, s := range samples {
+		v := math.Round(float64(s) * g)
+		switch {
+		case v > math.MaxInt16:
+			v = math.MaxInt16
+		case v < math.MinInt16:
+			v = math.MinInt16
+		}
+		samples[i] = int16(v)
+	}
+}
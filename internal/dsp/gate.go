@@ -0,0 +1,74 @@
+package dsp
+
+import "math"
+
+// noiseGateWindowSeconds is the detection window: each window's level opens
+// or closes the gate.
+const noiseGateWindowSeconds = 0.01
+
+// NoiseGate silences interleaved S16 audio whose level falls below a
+// threshold, ramping the gain over the attack (opening) and release
+// (closing) times so speech onsets and tails aren't chopped.
+type NoiseGate struct {
+	threshold   float64 // normalised RMS
+	channels    int
+	window      int // frames per detection window
+	attackStep  float64
+	releaseStep float64
+	gain        float64
+}
+
+// NewNoiseGate returns a gate for audio at sampleRate with channels
+// interleaved, closing below thresholdDBFS. attack and release are in seconds.
+func NewNoiseGate(sampleRate, channels int, thresholdDBFS, attack, release float64) *NoiseGate {
+	channels = max(channels, 1)
+	rampStep := func(seconds float64) float64 {
+		frames := seconds * float64(sampleRate)
+		if frames < 1 {
+			return 1
+		}
+		return 1 / frames
+	}
+	return &NoiseGate{
+		threshold:   math.Pow(10, thresholdDBFS/20),
+		channels:    channels,
+		window:      max(int(noiseGateWindowSeconds*float64(sampleRate)), 1),
+		attackStep:  rampStep(attack),
+		releaseStep: rampStep(release),
+		gain:        1,
+	}
+}
+
+// ProcessS16LE gates little-endian 16-bit PCM in place. State carries across
+// calls, so a stream can be processed buffer by buffer. A trailing partial
+// frame is left untouched.
+func (g *NoiseGate) ProcessS16LE(b []byte) {
+	frameBytes := 2 * g.channels
+	frames := len(b) / frameBytes
+	for start := 0; start < frames; start += g.window {
+		end := min(start+g.window, frames)
+		chunk := b[start*frameBytes : end*frameBytes]
+		rms, _, _ := MeasureS16LE(chunk)
+		target, step := 0.0, g.releaseStep
+		if rms >= g.threshold {
+			target, step = 1, g.attackStep
+		}
+		for f := 0; f < end-start; f++ {
+			if g.gain < target {
+				g.gain = min(g.gain+step, target)
+			} else if g.gain > target {
+				g.gain = max(g.gain-step, target)
+			}
+			if g.gain == 1 {
+				continue
+			}
+			for ch := 0; ch < g.channels; ch++ {
+				i := (f*g.channels + ch) * 2
+				s := int16(uint16(chunk[i]) | uint16(chunk[i+1])<<8)
+				v := int16(math.Round(float64(s) * g.gain))
+				chunk[i] = byte(uint16(v))
+				chunk[i+1] = byte(uint16(v) >> 8)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package dsp
+
+import "math"
+
+// Gating constants from ITU-R BS.1770-4 / EBU R128.
+const (
+	loudnessStepSeconds  = 0.1 // blocks are 400 ms with 75% overlap
+	loudnessBlockSteps   = 4
+	loudnessAbsoluteGate = -70.0
+	loudnessRelativeGate = -10.0
+)
+
+// biquad is a direct form I second-order IIR filter.
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+	return y
+}
+
+// kWeighting returns the two BS.1770 K-weighting stages (a high-frequency
+// shelf and a high-pass) designed for sampleRate.
+func kWeighting(sampleRate int) (shelf, highpass biquad) {
+	fs := float64(sampleRate)
+
+	// +4 dB shelf above ~1.5 kHz
+	a := math.Pow(10, 4.0/40)
+	w0 := 2 * math.Pi * 1500 / fs
+	alpha := math.Sin(w0) / math.Sqrt2 // Q = 1/√2
+	cosw := math.Cos(w0)
+	sqrtA := math.Sqrt(a)
+	a0 := (a + 1) - (a-1)*cosw + 2*sqrtA*alpha
+	shelf = biquad{
+		b0: a * ((a + 1) + (a-1)*cosw + 2*sqrtA*alpha) / a0,
+		b1: -2 * a * ((a - 1) + (a+1)*cosw) / a0,
+		b2: a * ((a + 1) + (a-1)*cosw - 2*sqrtA*alpha) / a0,
+		a1: 2 * ((a - 1) - (a+1)*cosw) / a0,
+		a2: ((a + 1) - (a-1)*cosw - 2*sqrtA*alpha) / a0,
+	}
+
+	// High-pass at ~38 Hz
+	w0 = 2 * math.Pi * 38 / fs
+	alpha = math.Sin(w0) // Q = 0.5
+	cosw = math.Cos(w0)
+	a0 = 1 + alpha
+	highpass = biquad{
+		b0: (1 + cosw) / 2 / a0,
+		b1: -(1 + cosw) / a0,
+		b2: (1 + cosw) / 2 / a0,
+		a1: -2 * cosw / a0,
+		a2: (1 - alpha) / a0,
+	}
+	return shelf, highpass
+}
+
+// LoudnessMeter measures integrated loudness (LUFS) of interleaved S16
+// audio per EBU R128: K-weighting, 400 ms blocks with 75% overlap, and
+// absolute (-70 LUFS) then relative (-10 LU) gating. Every channel is
+// weighted 1.0, which is correct for mono and stereo.
+type LoudnessMeter struct {
+	channels int
+	shelf    []biquad
+	highpass []biquad
+
+	stepSamples int       // frames per 100 ms step
+	inStep      int       // frames so far in the current step
+	stepPower   float64   // summed channel mean-square energy of the current step
+	steps       []float64 // energy of each completed step
+}
+
+// NewLoudnessMeter returns a meter for audio at sampleRate with channels interleaved.
+func NewLoudnessMeter(sampleRate, channels int) *LoudnessMeter {
+	channels = max(channels, 1)
+	m := &LoudnessMeter{
+		channels:    channels,
+		shelf:       make([]biquad, channels),
+		highpass:    make([]biquad, channels),
+		stepSamples: max(int(float64(sampleRate)*loudnessStepSeconds), 1),
+	}
+	for ch := range channels {
+		m.shelf[ch], m.highpass[ch] = kWeighting(sampleRate)
+	}
+	return m
+}
+
+// Add feeds interleaved samples; a trailing partial frame is ignored.
+func (m *LoudnessMeter) Add(samples []int16) {
+	frames := len(samples) / m.channels
+	for i := 0; i < frames; i++ {
+		for ch := 0; ch < m.channels; ch++ {
+			x := float64(samples[i*m.channels+ch]) / FullScale
+			y := m.highpass[ch].process(m.shelf[ch].process(x))
+			m.stepPower += y * y
+		}
+		m.inStep++
+		if m.inStep == m.stepSamples {
+			m.steps = append(m.steps, m.stepPower/float64(m.stepSamples))
+			m.inStep, m.stepPower = 0, 0
+		}
+	}
+}
+
+// Integrated returns the gated integrated loudness in LUFS, or -Inf when the
+// audio is shorter than one block or entirely below the absolute gate.
+func (m *LoudnessMeter) Integrated() float64 {
+	var blocks []float64
+	for i := 0; i+loudnessBlockSteps <= len(m.steps); i++ {
+		var sum float64
+		for _, p := range m.steps[i : i+loudnessBlockSteps] {
+			sum += p
+		}
+		blocks = append(blocks, sum/loudnessBlockSteps)
+	}
+
+	gated := func(threshold float64) float64 {
+		var sum float64
+		var n int
+		for _, p := range blocks {
+			if blockLoudness(p) > threshold {
+				sum += p
+				n++
+			}
+		}
+		if n == 0 {
+			return math.Inf(-1)
+		}
+		return blockLoudness(sum / float64(n))
+	}
+
+	abs := gated(loudnessAbsoluteGate)
+	if math.IsInf(abs, -1) {
+		return abs
+	}
+	return gated(abs + loudnessRelativeGate)
+}
+
+// blockLoudness converts summed channel mean-square energy to LUFS.
+func blockLoudness(power float64) float64 {
+	if power <= 0 {
+		return math.Inf(-1)
+	}
+	return -0.691 + 10*math.Log10(power)
+}
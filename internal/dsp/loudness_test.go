@@ -0,0 +1,57 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLoudnessMeterIntegrated(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate, ch int
+		samples  []int16
+		want     float64 // LUFS, -Inf for no measurement
+	}{
+		// A -20 dBFS 997 Hz sine reads about -23 LUFS in mono and -20 LUFS
+		// in stereo, where both channels add to the sum.
+		{"mono 48k", 48000, 1, tone(48000, 1, 997, 5, -20), -23.05},
+		{"stereo 48k", 48000, 2, tone(48000, 2, 997, 5, -20), -20.04},
+		{"mono 44.1k", 44100, 1, tone(44100, 1, 997, 5, -20), -23.05},
+		{"mono 16k", 16000, 1, tone(16000, 1, 997, 5, -20), -23.05},
+		{"silence", 48000, 2, make([]int16, 48000*2*2), math.Inf(-1)},
+		{"below absolute gate", 48000, 1, tone(48000, 1, 997, 2, -75), math.Inf(-1)},
+		{"shorter than a block", 48000, 1, tone(48000, 1, 997, 0.3, -20), math.Inf(-1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewLoudnessMeter(tt.rate, tt.ch)
+			// Feed in uneven pieces so step boundaries fall mid-buffer
+			for s := tt.samples; len(s) > 0; {
+				n := min(len(s), 1234*tt.ch)
+				m.Add(s[:n])
+				s = s[n:]
+			}
+			got := m.Integrated()
+			if math.IsInf(tt.want, -1) {
+				if !math.IsInf(got, -1) {
+					t.Errorf("Integrated = %.2f, want -Inf", got)
+				}
+				return
+			}
+			if math.Abs(got-tt.want) > 0.1 {
+				t.Errorf("Integrated = %.2f LUFS, want %.2f ±0.1", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoudnessMeterRelativeGate checks that quiet passes more than 10 LU
+// below the programme don't drag the integrated reading down.
+func TestLoudnessMeterRelativeGate(t *testing.T) {
+	m := NewLoudnessMeter(48000, 1)
+	m.Add(tone(48000, 1, 997, 5, -20))
+	m.Add(tone(48000, 1, 997, 5, -50))
+	if got := m.Integrated(); math.Abs(got+23.05) > 0.2 {
+		t.Errorf("Integrated = %.2f LUFS, want about -23.05", got)
+	}
+}
@@ -0,0 +1,90 @@
+package dsp
+
+// Downmix averages interleaved frames of channels samples into mono, reusing
+// dst when it has capacity. A trailing partial frame is ignored.
+func Downmix(dst, samples []int16, channels int) []int16 {
+	if channels <= 1 {
+		return append(dst[:0], samples...)
+	}
+	n := len(samples) / channels
+	if cap(dst) < n {
+		dst = make([]int16, n)
+	}
+	dst = dst[:n]
+	for i := 0; i < n; i++ {
+		var sum int32
+		for c := 0; c < channels; c++ {
+			sum += int32(samples[i*channels+c])
+		}
+		dst[i] = int16(sum / int32(channels))
+	}
+	return dst
+}
+
+// Resampler converts mono audio between sample rates using linear
+// interpolation. When downsampling, input is first smoothed with a moving
+// average as long as the rate ratio to limit aliasing. State carries across
+// Process calls so a stream can be converted block by block.
+type Resampler struct {
+	step float64 // input samples advanced per output sample
+	pos  float64 // position of the next output relative to the current block
+	last int16   // last filtered sample of the previous block
+	have bool
+
+	// moving-average filter state
+	window []int16
+	next   int
+	sum    int32
+}
+
+// NewResampler returns a Resampler from fromRate to toRate Hz.
+func NewResampler(fromRate, toRate int) *Resampler {
+	r := &Resampler{step: float64(fromRate) / float64(toRate)}
+	if k := (fromRate + toRate/2) / toRate; k > 1 {
+		r.window = make([]int16, k)
+	}
+	return r
+}
+
+// Process resamples the next block of input, appending output to dst.
+func (r *Resampler) Process(dst, in []int16) []int16 {
+	if len(in) == 0 {
+		return dst
+	}
+	if r.window != nil {
+		filtered := make([]int16, len(in))
+		for i, s := range in {
+			r.sum += int32(s) - int32(r.window[r.next])
+			r.window[r.next] = s
+			r.next = (r.next + 1) % len(r.window)
+			filtered[i] = int16(r.sum / int32(len(r.window)))
+		}
+		in = filtered
+	}
+
+	// at(-1) is the last sample of the previous block
+	at := func(i int) float64 {
+		if i < 0 {
+			return float64(r.last)
+		}
+		return float64(in[i])
+	}
+	if !r.have {
+		r.last = in[0]
+		r.have = true
+	}
+	n := len(in)
+	for r.pos < float64(n-1) {
+		i := int(r.pos)
+		if r.pos < 0 {
+			i = -1
+		}
+		frac := r.pos - float64(i)
+		v := at(i) + (at(i+1)-at(i))*frac
+		dst = append(dst, int16(v))
+		r.pos += r.step
+	}
+	r.pos -= float64(n)
+	r.last = in[n-1]
+	return dst
+}
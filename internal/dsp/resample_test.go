@@ -0,0 +1,114 @@
+package dsp
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// tone returns seconds of a sine at freq Hz with the given peak in dBFS,
+// repeated on every channel.
+func tone(sampleRate, channels int, freq, seconds, peakDBFS float64) []int16 {
+	amp := math.Pow(10, peakDBFS/20) * FullScale
+	n := int(float64(sampleRate) * seconds)
+	out := make([]int16, 0, n*channels)
+	for i := range n {
+		v := int16(math.Round(amp * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate))))
+		for range channels {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func TestDownmix(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []int16
+		channels int
+		want     []int16
+	}{
+		{"mono copies", []int16{1, -2, 3}, 1, []int16{1, -2, 3}},
+		{"zero channels copies", []int16{1, 2}, 0, []int16{1, 2}},
+		{"stereo averages", []int16{100, 300, -100, -300, 32767, 32767}, 2, []int16{200, -200, 32767}},
+		{"no overflow at full scale", []int16{-32768, -32768, 32767, 32767}, 2, []int16{-32768, 32767}},
+		{"truncates toward zero", []int16{-3, 0, 3, 0}, 2, []int16{-1, 1}},
+		{"partial frame dropped", []int16{10, 20, 30, 40, 50}, 2, []int16{15, 35}},
+		{"three channels", []int16{3, 6, 9}, 3, []int16{6}},
+		{"empty", nil, 2, []int16{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Downmix(nil, tt.samples, tt.channels); !slices.Equal(got, tt.want) {
+				t.Errorf("Downmix = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// dst is reused when it has room
+	dst := make([]int16, 0, 8)
+	if got := Downmix(dst, []int16{1, 3, 5, 7}, 2); &got[0] != &dst[:1][0] {
+		t.Error("Downmix allocated despite dst having capacity")
+	}
+}
+
+func TestResampler(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+	}{
+		{"48k to 16k", 48000, 16000},
+		{"44.1k to 16k", 44100, 16000},
+		{"32k to 16k", 32000, 16000},
+		{"16k unchanged", 16000, 16000},
+		{"8k to 16k", 8000, 16000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tone(tt.from, 1, 440, 1, -6)
+
+			whole := NewResampler(tt.from, tt.to).Process(nil, in)
+			wantLen := len(in) * tt.to / tt.from
+			if d := len(whole) - wantLen; d < -2 || d > 2 {
+				t.Errorf("len = %d, want %d ±2", len(whole), wantLen)
+			}
+
+			// Block-by-block output matches a single call, give or take the
+			// rounding of the fractional position carried between blocks
+			r := NewResampler(tt.from, tt.to)
+			var blocks []int16
+			for s := in; len(s) > 0; {
+				n := min(len(s), 777)
+				blocks = r.Process(blocks, s[:n])
+				s = s[n:]
+			}
+			if len(blocks) != len(whole) {
+				t.Fatalf("blockwise len = %d, one-shot %d", len(blocks), len(whole))
+			}
+			for i := range blocks {
+				if d := int(blocks[i]) - int(whole[i]); d < -1 || d > 1 {
+					t.Fatalf("blockwise[%d] = %d, one-shot %d", i, blocks[i], whole[i])
+				}
+			}
+
+			// A 440 Hz tone is well inside every passband, so its level holds
+			if got, want := DBFS(RMS(whole)), DBFS(RMS(in)); math.Abs(got-want) > 0.5 {
+				t.Errorf("level = %.2f dBFS, want %.2f ±0.5", got, want)
+			}
+		})
+	}
+}
+
+func TestResamplerDC(t *testing.T) {
+	in := make([]int16, 4800)
+	for i := range in {
+		in[i] = 1000
+	}
+	out := NewResampler(48000, 16000).Process(nil, in)
+	// Skip the anti-alias filter filling up from silence
+	for i, v := range out[1:] {
+		if v != 1000 {
+			t.Fatalf("out[%d] = %d, want 1000", i+1, v)
+		}
+	}
+}
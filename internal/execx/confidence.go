@@ -0,0 +1,48 @@
+package execx
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"strings"
+)
+
+// whisperJSON is the part of whisper's full JSON output (-ojf) used for scoring.
+type whisperJSON struct {
+	Transcription []struct {
+		Tokens []struct {
+			Text string  `json:"text"`
+			P    float64 `json:"p"`
+		} `json:"tokens"`
+	} `json:"transcription"`
+}
+
+// JSONConfidence reads whisper's full JSON output and returns a 0-1
+// confidence: the exponent of the mean token log-probability. Special tokens
+// such as [_BEG_] and timestamps are ignored.
+func JSONConfidence(path string) (float64, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var doc whisperJSON
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return 0, err
+	}
+	var sum float64
+	var n int
+	for _, seg := range doc.Transcription {
+		for _, tok := range seg.Tokens {
+			if strings.HasPrefix(tok.Text, "[_") || tok.P <= 0 {
+				continue
+			}
+			sum += math.Log(min(tok.P, 1))
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, errors.New("no scored tokens in whisper JSON")
+	}
+	return math.Exp(sum / float64(n)), nil
+}
@@ -2,19 +2,33 @@ package execx
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"syscall"
+	"time"
+
+	"blackbox/internal/pathx"
+	"blackbox/internal/wav"
 )
 
 // BuildWhisperArgs builds arguments for whisper.cpp CLI.
 // It uses -m <model> -f <wav> -otxt and, if outBase provided, -of <outBase>.
-func BuildWhisperArgs(modelPath, wavPath, lang string, threads int, outBase string, extraArgs string) []string {
+// opts adds -osrt -ovtt for timed captions and -ojf for token probabilities.
+func BuildWhisperArgs(modelPath, wavPath, lang string, threads int, outBase, extraArgs string, opts WhisperOptions) []string {
 	args := []string{"-m", modelPath, "-f", wavPath, "-otxt"}
+	if opts.Subtitles {
+		args = append(args, "-osrt", "-ovtt")
+	}
+	if opts.Confidence {
+		args = append(args, "-ojf") // full JSON with token probabilities
+	}
 	if lang != "" {
 		args = append(args, "-l", lang)
 	}
@@ -35,19 +49,87 @@ func BuildWhisperArgs(modelPath, wavPath, lang string, threads int, outBase stri
 // RunWhisper runs the whisper binary and returns the transcript .txt path.
 // Logs are written to outDir/<base>.log.
 func RunWhisper(whisperBin, modelPath, wavPath, outDir, lang string, threads int, extraArgs string) (string, error) {
-	if _, err := os.Stat(wavPath); err != nil {
+	return RunWhisperWithProgress(context.Background(), whisperBin, modelPath, wavPath, outDir, lang, threads, extraArgs, nil)
+}
+
+// RunWhisperWithProgress is RunWhisper with onProgress called as whisper works,
+// with a 0-100 percentage and the latest segment line (empty for plain
+// progress updates). Calls are serialised. onProgress may be nil.
+// Cancelling ctx kills whisper and removes its partial output.
+func RunWhisperWithProgress(ctx context.Context, whisperBin, modelPath, wavPath, outDir, lang string, threads int, extraArgs string, onProgress func(pct float64, segment string)) (string, error) {
+	out, err := runWhisper(ctx, whisperBin, modelPath, wavPath, outDir, lang, threads, WhisperOptions{}, extraArgs, onProgress)
+	return out.Txt, err
+}
+
+// WhisperOutputs are the files produced by a whisper run. SRT and VTT are
+// empty unless subtitles were requested and whisper wrote them; JSON likewise
+// for confidence.
+type WhisperOutputs struct {
+	Txt  string
+	SRT  string
+	VTT  string
+	JSON string
+
+	// Confidence is the geometric mean token probability (0-1) from JSON,
+	// or 0 when it wasn't requested or couldn't be read.
+	Confidence float64
+}
+
+// WhisperOptions selects the extra outputs of RunWhisperWithOptions.
+type WhisperOptions struct {
+	Subtitles  bool // <base>.srt and <base>.vtt captions
+	Confidence bool // <base>.json with token probabilities, scored into Confidence
+}
+
+// RunWhisperWithOptions is RunWhisperWithProgress with the extra outputs in opts.
+func RunWhisperWithOptions(ctx context.Context, whisperBin, modelPath, wavPath, outDir, lang string, threads int, extraArgs string, opts WhisperOptions, onProgress func(pct float64, segment string)) (WhisperOutputs, error) {
+	return runWhisper(ctx, whisperBin, modelPath, wavPath, outDir, lang, threads, opts, extraArgs, onProgress)
+}
+
+func runWhisper(ctx context.Context, whisperBin, modelPath, wavPath, outDir, lang string, threads int, opts WhisperOptions, extraArgs string, onProgress func(pct float64, segment string)) (WhisperOutputs, error) {
+	txtPath, err := whisperTxt(ctx, whisperBin, modelPath, wavPath, outDir, lang, threads, opts, extraArgs, onProgress)
+	if err != nil {
+		return WhisperOutputs{}, err
+	}
+	out := WhisperOutputs{Txt: txtPath}
+	base := strings.TrimSuffix(txtPath, filepath.Ext(txtPath))
+	if opts.Subtitles {
+		if _, err := os.Stat(base + ".srt"); err == nil {
+			out.SRT = base + ".srt"
+		}
+		if _, err := os.Stat(base + ".vtt"); err == nil {
+			out.VTT = base + ".vtt"
+		}
+	}
+	if opts.Confidence {
+		if c, err := JSONConfidence(base + ".json"); err == nil {
+			out.JSON = base + ".json"
+			out.Confidence = c
+		}
+	}
+	return out, nil
+}
+
+// whisperTxt runs whisper and returns the transcript .txt path, recovering it
+// from other names or the console output when -of isn't honoured.
+func whisperTxt(ctx context.Context, whisperBin, modelPath, wavPath, outDir, lang string, threads int, opts WhisperOptions, extraArgs string, onProgress func(pct float64, segment string)) (string, error) {
+	if _, err := os.Stat(pathx.Long(wavPath)); err != nil {
 		return "", fmt.Errorf("wav missing: %w", err)
 	}
+	// whisper-cli opens files without long-path support, so fail clearly up front
+	if pathx.TooLong(wavPath) {
+		return "", fmt.Errorf("wav path exceeds %d characters, move the file or shorten the directory: %s", pathx.MaxPath, wavPath)
+	}
 	if whisperBin == "" {
 		return "", errors.New("whisper binary not specified")
 	}
 	if _, err := os.Stat(whisperBin); err != nil {
 		return "", fmt.Errorf("whisper binary missing: %w", err)
 	}
-	if _, err := os.Stat(modelPath); err != nil {
-		return "", fmt.Errorf("model missing: %w", err)
+	if err := ValidateModel(modelPath); err != nil {
+		return "", err
 	}
-	if err := os.MkdirAll(outDir, 0755); err != nil {
+	if err := os.MkdirAll(pathx.Long(outDir), 0755); err != nil {
 		return "", err
 	}
 
@@ -55,21 +137,40 @@ func RunWhisper(whisperBin, modelPath, wavPath, outDir, lang string, threads int
 	outBase := filepath.Join(outDir, baseName)
 	txtPath := outBase + ".txt"
 	logPath := outBase + ".log"
+	if pathx.TooLong(logPath) {
+		return "", fmt.Errorf("transcript path exceeds %d characters, shorten the output directory: %s", pathx.MaxPath, logPath)
+	}
 
-	args := BuildWhisperArgs(modelPath, wavPath, lang, threads, outBase, extraArgs)
+	if onProgress != nil {
+		extraArgs = strings.TrimSpace(extraArgs + " -pp") // print progress
+	}
+	args := BuildWhisperArgs(modelPath, wavPath, lang, threads, outBase, extraArgs, opts)
 
-	cmd := exec.Command(whisperBin, args...)
+	cmd := exec.CommandContext(ctx, whisperBin, args...)
 	var stdoutBuf, stderrBuf bytes.Buffer
 	cmd.Stdout = &stdoutBuf
 	cmd.Stderr = &stderrBuf
+	if onProgress != nil {
+		total, _ := wav.Duration(pathx.Long(wavPath))
+		p := &progressParser{total: total, onProgress: onProgress}
+		cmd.Stdout = io.MultiWriter(&stdoutBuf, &lineWriter{onLine: p.line})
+		cmd.Stderr = io.MultiWriter(&stderrBuf, &lineWriter{onLine: p.line})
+	}
 
 	// Hide CMD window on Windows
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		HideWindow: true,
 	}
 
+	started := time.Now()
 	err := cmd.Run()
 
+	// Cancelled runs leave nothing behind; earlier outputs are kept
+	if ctx.Err() != nil {
+		removeNewer(started, txtPath, logPath, outBase+".srt", outBase+".vtt", outBase+".json")
+		return "", fmt.Errorf("whisper canceled: %w", ctx.Err())
+	}
+
 	// Write combined logs
 	_ = os.WriteFile(logPath, append(stdoutBuf.Bytes(), stderrBuf.Bytes()...), 0644)
 
@@ -81,11 +182,103 @@ func RunWhisper(whisperBin, modelPath, wavPath, outDir, lang string, threads int
 		return txtPath, nil
 	}
 
+	// Some builds ignore -of and name the output after the input (e.g. <wav>.txt)
+	if found := findWhisperOutput(wavPath, outDir, baseName, started); found != "" {
+		if err := moveFile(found, txtPath); err == nil {
+			return txtPath, nil
+		}
+		return found, nil
+	}
+
 	// Fallback: create txt from stdout if flag unsupported
 	if stdoutBuf.Len() > 0 {
 		if writeErr := os.WriteFile(txtPath, stdoutBuf.Bytes(), 0644); writeErr == nil {
 			return txtPath, nil
 		}
 	}
-	return "", fmt.Errorf("transcript not produced: expected %s", txtPath)
+
+	// Last resort: builds that only log segments to stderr
+	if text := parseSegmentLines(stderrBuf.String()); text != "" {
+		if writeErr := os.WriteFile(txtPath, []byte(text), 0644); writeErr == nil {
+			return txtPath, nil
+		}
+	}
+	return "", fmt.Errorf("transcript not produced: expected %s (see %s)", txtPath, logPath)
+}
+
+// findWhisperOutput looks for a .txt written since started whose name begins
+// with baseName, next to the input or in outDir. Returns "" if none is found.
+func findWhisperOutput(wavPath, outDir, baseName string, started time.Time) string {
+	candidates := []string{wavPath + ".txt"}
+	for _, dir := range []string{outDir, filepath.Dir(wavPath)} {
+		matches, _ := filepath.Glob(filepath.Join(dir, globEscape(baseName)+"*.txt"))
+		candidates = append(candidates, matches...)
+	}
+	// Allow for coarse filesystem timestamps
+	cutoff := started.Add(-2 * time.Second)
+	for _, c := range candidates {
+		info, err := os.Stat(c)
+		if err != nil || info.IsDir() || info.Size() == 0 || info.ModTime().Before(cutoff) {
+			continue
+		}
+		return c
+	}
+	return ""
+}
+
+// removeNewer deletes those of paths modified since started.
+func removeNewer(started time.Time, paths ...string) {
+	cutoff := started.Add(-2 * time.Second)
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && !info.ModTime().Before(cutoff) {
+			_ = os.Remove(p)
+		}
+	}
+}
+
+// globEscape escapes glob metacharacters in a literal file name.
+func globEscape(s string) string {
+	r := strings.NewReplacer("*", "[*]", "?", "[?]", "[", "[[]")
+	return r.Replace(s)
+}
+
+// moveFile renames src to dst, copying when a rename isn't possible (e.g. across volumes).
+func moveFile(src, dst string) error {
+	if src == dst {
+		return nil
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dst, b, 0644); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// segmentLine matches whisper's console output, e.g.
+// "[00:00:00.000 --> 00:00:04.200]   Hello there."
+var segmentLine = regexp.MustCompile(`^\[\d{2}:\d{2}:\d{2}[.,]\d{3} --> \d{2}:\d{2}:\d{2}[.,]\d{3}\]\s*(.*)$`)
+
+// parseSegmentLines extracts segment text from whisper console output, one
+// segment per line. Returns "" if there are no segment lines.
+func parseSegmentLines(output string) string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		m := segmentLine.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		if text := strings.TrimSpace(m[1]); text != "" {
+			lines = append(lines, text)
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
 }
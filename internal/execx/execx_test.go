@@ -0,0 +1,193 @@
+package execx
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMain lets the test binary stand in for whisper-cli; see fakeWhisper.
+func TestMain(m *testing.M) {
+	if mode := os.Getenv("BLACKBOX_FAKE_WHISPER"); mode != "" {
+		os.Exit(runFakeWhisper(mode, os.Args[1:]))
+	}
+	os.Exit(m.Run())
+}
+
+// runFakeWhisper mimics whisper builds that ignore -of: "input-name" writes
+// <wav>.txt next to the input, "log-only" prints segments to stderr.
+func runFakeWhisper(mode string, args []string) int {
+	var wavPath string
+	for i, arg := range args {
+		if arg == "-f" && i+1 < len(args) {
+			wavPath = args[i+1]
+		}
+	}
+	switch mode {
+	case "input-name":
+		if err := os.WriteFile(wavPath+".txt", []byte("Hello from whisper.\n"), 0644); err != nil {
+			return 1
+		}
+	case "log-only":
+		fmt.Fprintln(os.Stderr, "whisper_init_from_file: loading model")
+		fmt.Fprintln(os.Stderr, "[00:00:00.000 --> 00:00:02.000]   Hello from")
+		fmt.Fprintln(os.Stderr, "[00:00:02.000 --> 00:00:04.000]   whisper.")
+	}
+	return 0
+}
+
+// fakeWhisper returns the test binary, a placeholder model and a WAV to transcribe.
+func fakeWhisper(t *testing.T, mode string) (bin, model, wavPath string) {
+	t.Helper()
+	t.Setenv("BLACKBOX_FAKE_WHISPER", mode)
+	bin, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	b := make([]byte, minModelSize)
+	copy(b, "GGUF")
+	model = filepath.Join(dir, "ggml-test.bin")
+	if err := os.WriteFile(model, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	wavPath = filepath.Join(dir, "call.wav")
+	if err := os.WriteFile(wavPath, []byte("RIFF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return bin, model, wavPath
+}
+
+func TestRunWhisperRecoversOutput(t *testing.T) {
+	tests := []struct {
+		mode, want string
+	}{
+		{"input-name", "Hello from whisper.\n"},
+		{"log-only", "Hello from\nwhisper.\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			bin, model, wavPath := fakeWhisper(t, tt.mode)
+			outDir := t.TempDir()
+			txt, err := RunWhisper(bin, model, wavPath, outDir, "en", 0, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(outDir, "call.txt"); txt != want {
+				t.Errorf("transcript at %s, want %s", txt, want)
+			}
+			got, err := os.ReadFile(txt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("transcript = %q, want %q", got, tt.want)
+			}
+			if _, err := os.Stat(wavPath + ".txt"); !os.IsNotExist(err) {
+				t.Errorf("input-named output left behind (stat err %v)", err)
+			}
+		})
+	}
+}
+
+func TestFindWhisperOutput(t *testing.T) {
+	started := time.Now()
+	old := started.Add(-time.Hour)
+	tests := []struct {
+		name  string
+		files map[string]time.Time // relative to the input dir ("in/") or outDir ("out/")
+		empty string               // a file written with no content
+		want  string
+	}{
+		{"input name", map[string]time.Time{"in/call.wav.txt": started}, "", "in/call.wav.txt"},
+		{"suffixed in outDir", map[string]time.Time{"out/call_0.txt": started}, "", "out/call_0.txt"},
+		{"suffixed next to input", map[string]time.Time{"in/call-transcript.txt": started}, "", "in/call-transcript.txt"},
+		{"stale output ignored", map[string]time.Time{"out/call_0.txt": old}, "", ""},
+		{"empty output ignored", map[string]time.Time{}, "out/call.txt", ""},
+		{"other recording ignored", map[string]time.Time{"out/meeting.txt": started}, "", ""},
+		{"nothing written", nil, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, d := range []string{"in", "out"} {
+				if err := os.Mkdir(filepath.Join(root, d), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for name, mod := range tt.files {
+				p := filepath.Join(root, name)
+				if err := os.WriteFile(p, []byte("text"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chtimes(p, mod, mod); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.empty != "" {
+				if err := os.WriteFile(filepath.Join(root, tt.empty), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := findWhisperOutput(filepath.Join(root, "in", "call.wav"), filepath.Join(root, "out"), "call", started)
+			if got != "" {
+				got = filepath.ToSlash(strings.TrimPrefix(got, root+string(filepath.Separator)))
+			}
+			if got != tt.want {
+				t.Errorf("found %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSegmentLines(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"segments", "[00:00:00.000 --> 00:00:04.200]   Hello there.\n[00:00:04.200 --> 00:00:06.000]  General Kenobi.\n", "Hello there.\nGeneral Kenobi.\n"},
+		{"mixed with log lines", "whisper_init: loading\r\n  [00:00:00,000 --> 00:00:01,500] Comma timestamps.\r\nwhisper_print_timings: total\n", "Comma timestamps.\n"},
+		{"blank segments skipped", "[00:00:00.000 --> 00:00:01.000]   \n[00:00:01.000 --> 00:00:02.000] Text\n", "Text\n"},
+		{"no segments", "whisper_init: loading\nerror: failed to open file\n", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseSegmentLines(tt.in); got != tt.want {
+				t.Errorf("parseSegmentLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildWhisperArgs(t *testing.T) {
+	base := []string{"-m", "model.bin", "-f", "call.wav", "-otxt"}
+	tests := []struct {
+		name      string
+		lang      string
+		threads   int
+		outBase   string
+		extraArgs string
+		opts      WhisperOptions
+		want      []string
+	}{
+		{"minimal", "", 0, "", "", WhisperOptions{}, base},
+		{"subtitles", "", 0, "", "", WhisperOptions{Subtitles: true}, append(base, "-osrt", "-ovtt")},
+		{"confidence", "", 0, "", "", WhisperOptions{Confidence: true}, append(base, "-ojf")},
+		{
+			"everything", "de", 4, "out/call", " -bs 5  -pp ", WhisperOptions{Subtitles: true, Confidence: true},
+			append(base, "-osrt", "-ovtt", "-ojf", "-l", "de", "-t", "4", "-of", "out/call", "-bs", "5", "-pp"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildWhisperArgs("model.bin", "call.wav", tt.lang, tt.threads, tt.outBase, tt.extraArgs, tt.opts)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("args = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package execx
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"blackbox/internal/pathx"
+)
+
+// FFmpegEncoder pipes raw PCM S16LE frames into an ffmpeg process that encodes
+// them to a compressed file. It satisfies wav.Encoder.
+type FFmpegEncoder struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	buf    *bufio.Writer
+	stderr bytes.Buffer
+	closed bool
+}
+
+// OpusCodecArgs are ffmpeg output arguments for speech-tuned Opus in an Ogg container.
+var OpusCodecArgs = []string{"-c:a", "libopus", "-b:a", "32k", "-application", "voip"}
+
+// FLACCodecArgs are ffmpeg output arguments for lossless FLAC at maximum compression.
+var FLACCodecArgs = []string{"-c:a", "flac", "-compression_level", "8"}
+
+// NewFFmpegEncoder starts ffmpeg reading PCM S16LE from stdin and writing outPath
+// using the given codec arguments.
+func NewFFmpegEncoder(ffmpegBin, outPath string, sampleRate, channels int, codecArgs []string) (*FFmpegEncoder, error) {
+	if ffmpegBin == "" {
+		return nil, errors.New("ffmpeg binary not specified")
+	}
+	if _, err := os.Stat(ffmpegBin); err != nil {
+		return nil, fmt.Errorf("ffmpeg binary missing: %w", err)
+	}
+
+	args := []string{
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-f", "s16le", "-ar", strconv.Itoa(sampleRate), "-ac", strconv.Itoa(channels), "-i", "pipe:0",
+	}
+	args = append(args, codecArgs...)
+	args = append(args, pathx.Long(outPath))
+
+	e := &FFmpegEncoder{cmd: exec.Command(ffmpegBin, args...)}
+	e.cmd.Stderr = &e.stderr
+	// Hide CMD window on Windows
+	e.cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow: true,
+	}
+	stdin, err := e.cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := e.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start ffmpeg: %w", err)
+	}
+	e.stdin = stdin
+	e.buf = bufio.NewWriterSize(stdin, 256<<10)
+	return e, nil
+}
+
+// Write queues PCM bytes for encoding.
+func (e *FFmpegEncoder) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, io.ErrClosedPipe
+	}
+	return e.buf.Write(p)
+}
+
+// Flush pushes buffered PCM to ffmpeg.
+func (e *FFmpegEncoder) Flush() error {
+	if e.closed {
+		return nil
+	}
+	return e.buf.Flush()
+}
+
+// Close flushes remaining PCM, closes ffmpeg's stdin and waits for it to finish the file.
+func (e *FFmpegEncoder) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	flushErr := e.buf.Flush()
+	_ = e.stdin.Close()
+	if err := e.cmd.Wait(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, bytes.TrimSpace(e.stderr.Bytes()))
+	}
+	return flushErr
+}
+
+// DecodeToWAV converts any ffmpeg-readable audio file to a 16 kHz mono PCM WAV,
+// the format whisper expects.
+func DecodeToWAV(ffmpegBin, srcPath, wavPath string) error {
+	if err := runFFmpeg(ffmpegBin, "-i", pathx.Long(srcPath), "-ar", "16000", "-ac", "1", "-c:a", "pcm_s16le", pathx.Long(wavPath)); err != nil {
+		return fmt.Errorf("ffmpeg decode failed: %w", err)
+	}
+	return nil
+}
+
+// ReencodeWAV rewrites whatever ffmpeg can decode from srcPath as a 16-bit PCM
+// WAV, keeping the source sample rate and channel count.
+func ReencodeWAV(ffmpegBin, srcPath, wavPath string) error {
+	if err := runFFmpeg(ffmpegBin, "-i", pathx.Long(srcPath), "-c:a", "pcm_s16le", pathx.Long(wavPath)); err != nil {
+		return fmt.Errorf("ffmpeg re-encode failed: %w", err)
+	}
+	return nil
+}
+
+// runFFmpeg runs ffmpeg quietly with args, overwriting the output. Errors
+// include ffmpeg's stderr. Unlike whisper-cli, ffmpeg opens \\?\ paths, so
+// callers pass file arguments through pathx.Long.
+func runFFmpeg(ffmpegBin string, args ...string) error {
+	if _, err := os.Stat(ffmpegBin); err != nil {
+		return fmt.Errorf("ffmpeg binary missing: %w", err)
+	}
+	cmd := exec.Command(ffmpegBin, append([]string{"-hide_banner", "-loglevel", "error", "-y"}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// Hide CMD window on Windows
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow: true,
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
+
+// AudioInfo is the format of an audio file's first audio stream as reported by ffmpeg.
+type AudioInfo struct {
+	SampleRate    int
+	Channels      int
+	BitsPerSample int           // 0 for codecs that decode to float, such as Opus
+	Duration      time.Duration // 0 if ffmpeg doesn't know it
+}
+
+// ProbeAudio reads the format and duration of path from ffmpeg's input summary,
+// for recordings that aren't WAV and so have no header to parse directly.
+func ProbeAudio(ffmpegBin, path string) (AudioInfo, error) {
+	if _, err := os.Stat(ffmpegBin); err != nil {
+		return AudioInfo{}, fmt.Errorf("ffmpeg binary missing: %w", err)
+	}
+	// With no output file ffmpeg prints the input summary and exits non-zero,
+	// so the exit status is ignored and stderr parsed instead.
+	cmd := exec.Command(ffmpegBin, "-hide_banner", "-i", pathx.Long(path))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// Hide CMD window on Windows
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow: true,
+	}
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return AudioInfo{}, fmt.Errorf("ffmpeg probe failed: %w", err)
+		}
+	}
+	info, err := parseProbeOutput(stderr.String())
+	if err != nil {
+		return AudioInfo{}, fmt.Errorf("ffmpeg probe of %s: %w", path, err)
+	}
+	return info, nil
+}
+
+var (
+	probeDurationRe = regexp.MustCompile(`Duration: (\d+):(\d{2}):(\d{2}(?:\.\d+)?)`)
+	// e.g. "Stream #0:0: Audio: flac, 48000 Hz, stereo, s16" or
+	// "Stream #0:0(eng): Audio: pcm_s24le, 44100 Hz, 2 channels, s32 (24 bit), 2116 kb/s"
+	probeAudioRe = regexp.MustCompile(`Stream #\S+: Audio: [^,]+, (\d+) Hz, ([^,]+), (\w+)(?: \((\d+) bit\))?`)
+)
+
+// channelLayouts maps ffmpeg's named channel layouts to channel counts.
+var channelLayouts = map[string]int{
+	"mono": 1, "stereo": 2, "2.1": 3, "3.0": 3, "quad": 4, "4.0": 4,
+	"5.0": 5, "5.1": 6, "6.1": 7, "7.1": 8,
+}
+
+// parseProbeOutput extracts the first audio stream's format and the duration
+// from ffmpeg's stderr.
+func parseProbeOutput(out string) (AudioInfo, error) {
+	m := probeAudioRe.FindStringSubmatch(out)
+	if m == nil {
+		return AudioInfo{}, errors.New("no audio stream found")
+	}
+	var info AudioInfo
+	info.SampleRate, _ = strconv.Atoi(m[1])
+
+	layout, _, _ := strings.Cut(strings.TrimSpace(m[2]), "(") // "5.1(side)"
+	if n, ok := channelLayouts[layout]; ok {
+		info.Channels = n
+	} else if count, ok := strings.CutSuffix(layout, " channels"); ok {
+		info.Channels, _ = strconv.Atoi(count)
+	}
+
+	if m[4] != "" {
+		info.BitsPerSample, _ = strconv.Atoi(m[4])
+	} else {
+		switch strings.TrimSuffix(m[3], "p") { // planar variants share the size
+		case "u8":
+			info.BitsPerSample = 8
+		case "s16":
+			info.BitsPerSample = 16
+		case "s32":
+			info.BitsPerSample = 32
+		case "s64":
+			info.BitsPerSample = 64
+		}
+	}
+
+	if d := probeDurationRe.FindStringSubmatch(out); d != nil {
+		hours, _ := strconv.Atoi(d[1])
+		mins, _ := strconv.Atoi(d[2])
+		secs, _ := strconv.ParseFloat(d[3], 64)
+		info.Duration = time.Duration(hours)*time.Hour + time.Duration(mins)*time.Minute +
+			time.Duration(secs*float64(time.Second))
+	}
+	return info, nil
+}
@@ -0,0 +1,64 @@
+package execx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseProbeOutput(t *testing.T) {
+	const header = "Input #0, ogg, from 'call.ogg':\n  Duration: 00:01:02.50, start: 0.000000, bitrate: 33 kb/s\n"
+	tests := []struct {
+		name    string
+		out     string
+		want    AudioInfo
+		wantErr bool
+	}{
+		{
+			"opus",
+			header + "  Stream #0:0: Audio: opus, 48000 Hz, mono, fltp\n",
+			AudioInfo{SampleRate: 48000, Channels: 1, Duration: 62500 * time.Millisecond},
+			false,
+		},
+		{
+			"flac",
+			header + "  Stream #0:0: Audio: flac, 16000 Hz, stereo, s16\n",
+			AudioInfo{SampleRate: 16000, Channels: 2, BitsPerSample: 16, Duration: 62500 * time.Millisecond},
+			false,
+		},
+		{
+			"24-bit wav with language and codec tag",
+			"  Duration: 01:00:00.00, bitrate: 2116 kb/s\n  Stream #0:0(eng): Audio: pcm_s24le ([1][0][0][0] / 0x0001), 44100 Hz, 2 channels, s32 (24 bit), 2116 kb/s\n",
+			AudioInfo{SampleRate: 44100, Channels: 2, BitsPerSample: 24, Duration: time.Hour},
+			false,
+		},
+		{
+			"surround layout, unknown duration",
+			"  Duration: N/A, bitrate: N/A\n  Stream #0:1: Audio: flac, 48000 Hz, 5.1(side), s32p\n",
+			AudioInfo{SampleRate: 48000, Channels: 6, BitsPerSample: 32},
+			false,
+		},
+		{
+			"no audio stream",
+			"Input #0, image2, from 'cover.png':\n  Stream #0:0: Video: png, rgba, 600x600\n",
+			AudioInfo{},
+			true,
+		},
+		{
+			"missing file",
+			"call.ogg: No such file or directory\n",
+			AudioInfo{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseProbeOutput(tt.out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package execx
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// minModelSize rejects files far smaller than any real whisper model
+// (ggml-tiny is ~75 MB); truncated downloads usually fail here.
+const minModelSize = 1 << 20
+
+// modelMagics are the leading bytes of GGML-family model files. GGML magics
+// are stored as little-endian uint32s, so "ggml" appears as "lmgg" on disk.
+var modelMagics = []string{"lmgg", "fmgg", "tjgg", "GGUF"}
+
+// ValidateModel checks that path looks like a complete GGML/GGUF model, so a
+// corrupt or partial download fails with a clear message instead of a
+// cryptic whisper crash.
+func ValidateModel(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("model missing: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("model unreadable: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("model path is a directory: %s", path)
+	}
+	if info.Size() < minModelSize {
+		return fmt.Errorf("model appears corrupt or incomplete (%d bytes), download it again: %s", info.Size(), path)
+	}
+	var magic [4]byte
+	if _, err := io.ReadFull(f, magic[:]); err != nil {
+		return fmt.Errorf("model unreadable: %w", err)
+	}
+	for _, m := range modelMagics {
+		if string(magic[:]) == m {
+			return nil
+		}
+	}
+	return fmt.Errorf("model appears corrupt or incomplete (not a GGML/GGUF file), download it again: %s", path)
+}
@@ -0,0 +1,52 @@
+package execx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateModel(t *testing.T) {
+	dir := t.TempDir()
+	model := func(name, magic string, size int) string {
+		b := make([]byte, size)
+		copy(b, magic)
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string // substring, "" for a valid model
+	}{
+		{"ggml", model("ggml.bin", "lmgg", minModelSize), ""},
+		{"ggmf", model("ggmf.bin", "fmgg", minModelSize), ""},
+		{"ggjt", model("ggjt.bin", "tjgg", minModelSize+1), ""},
+		{"gguf", model("gguf.bin", "GGUF", minModelSize), ""},
+		{"big-endian magic", model("be.bin", "ggml", minModelSize), "not a GGML/GGUF file"},
+		{"html error page", model("html.bin", "<!DOCTYPE html>", minModelSize), "not a GGML/GGUF file"},
+		{"truncated", model("short.bin", "lmgg", minModelSize-1), "corrupt or incomplete"},
+		{"empty", model("empty.bin", "", 0), "corrupt or incomplete"},
+		{"missing", filepath.Join(dir, "missing.bin"), "model missing"},
+		{"directory", dir, "is a directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateModel(tt.path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateModel: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateModel = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package execx
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// progressLine matches whisper's -pp output, e.g.
+// "whisper_print_progress_callback: progress =  40%"
+var progressLine = regexp.MustCompile(`progress\s*=\s*(\d+(?:\.\d+)?)%`)
+
+// segmentTimes captures the end timestamp of a segment line.
+var segmentTimes = regexp.MustCompile(`^\[\d{2}:\d{2}:\d{2}[.,]\d{3} --> (\d{2}):(\d{2}):(\d{2})[.,](\d{3})\]`)
+
+// progressParser turns whisper console lines into progress callbacks. Segment
+// lines report how far into the audio whisper has got when total is known.
+type progressParser struct {
+	mu         sync.Mutex
+	total      time.Duration
+	pct        float64
+	onProgress func(pct float64, segment string)
+}
+
+func (p *progressParser) line(line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if m := progressLine.FindStringSubmatch(line); m != nil {
+		if v, err := strconv.ParseFloat(m[1], 64); err == nil && v >= p.pct {
+			p.pct = min(v, 100)
+			p.onProgress(p.pct, "")
+		}
+		return
+	}
+	m := segmentTimes.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	if p.total > 0 {
+		h, _ := strconv.Atoi(m[1])
+		mi, _ := strconv.Atoi(m[2])
+		s, _ := strconv.Atoi(m[3])
+		ms, _ := strconv.Atoi(m[4])
+		end := time.Duration(h)*time.Hour + time.Duration(mi)*time.Minute +
+			time.Duration(s)*time.Second + time.Duration(ms)*time.Millisecond
+		if v := 100 * float64(end) / float64(p.total); v > p.pct {
+			p.pct = min(v, 100)
+		}
+	}
+	p.onProgress(p.pct, line)
+}
+
+// lineWriter calls onLine for each complete line written to it, without the
+// line terminator.
+type lineWriter struct {
+	buf    []byte
+	onLine func(line string)
+}
+
+func (w *lineWriter) Write(b []byte) (int, error) {
+	w.buf = append(w.buf, b...)
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+		if line := bytes.TrimSpace(w.buf[:i]); len(line) > 0 {
+			w.onLine(string(line))
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(b), nil
+}
@@ -0,0 +1,50 @@
+// Package pathx contains path helpers for Windows' MAX_PATH limit.
+package pathx
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// MaxPath is the classic Windows path limit (including the terminating NUL).
+const MaxPath = 260
+
+const (
+	longPrefix    = `\\?\`
+	longUNCPrefix = `\\?\UNC\`
+)
+
+// Long returns path in a form that Win32 file APIs accept beyond MAX_PATH.
+// On Windows, paths that are too long are made absolute and given the \\?\
+// prefix (\\?\UNC\ for network shares). Short paths, already-prefixed paths
+// and all paths on other platforms are returned unchanged.
+func Long(path string) string {
+	if runtime.GOOS != "windows" || !TooLong(path) || strings.HasPrefix(path, longPrefix) {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return addPrefix(abs)
+}
+
+// TooLong reports whether path would exceed MAX_PATH once made absolute.
+// External tools (whisper-cli, ffmpeg) generally can't open such paths.
+func TooLong(path string) bool {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return len(path) >= MaxPath
+}
+
+// addPrefix adds the extended-length prefix to a cleaned absolute path.
+// \\?\ disables Win32 normalisation, so separators must already be backslashes.
+func addPrefix(abs string) string {
+	abs = strings.ReplaceAll(abs, "/", `\`)
+	if strings.HasPrefix(abs, `\\`) {
+		return longUNCPrefix + strings.TrimPrefix(abs, `\\`)
+	}
+	return longPrefix + abs
+}
@@ -0,0 +1,57 @@
+package pathx
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestAddPrefix(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"drive", `C:\Users\me\out\call.wav`, `\\?\C:\Users\me\out\call.wav`},
+		{"forward slashes", `C:/Users/me/out/call.wav`, `\\?\C:\Users\me\out\call.wav`},
+		{"UNC", `\\server\share\out\call.wav`, `\\?\UNC\server\share\out\call.wav`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := addPrefix(tt.in); got != tt.want {
+				t.Errorf("addPrefix(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLong(t *testing.T) {
+	dir := t.TempDir()
+	short := filepath.Join(dir, "call.wav")
+	long := filepath.Join(dir, strings.Repeat("a", MaxPath), "call.wav")
+	prefixed := longPrefix + long
+
+	if TooLong(short) {
+		t.Errorf("TooLong(%q) = true", short)
+	}
+	if !TooLong(long) {
+		t.Errorf("TooLong of a %d-character path = false", len(long))
+	}
+
+	tests := []struct {
+		name, in, want string
+	}{
+		{"short", short, short},
+		{"already prefixed", prefixed, prefixed},
+		{"long", long, long}, // unchanged off Windows
+	}
+	if runtime.GOOS == "windows" {
+		tests[2].want = prefixed
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Long(tt.in); got != tt.want {
+				t.Errorf("Long(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"blackbox/internal/dsp"
+	"blackbox/internal/wav"
+)
+
+// Channel layout for stereo-split recordings: the microphone (local speaker)
+// is on the left channel and loopback (remote participants) on the right.
+const (
+	localChannel  = 0
+	remoteChannel = 1
+
+	speakerWindowSeconds = 0.1
+	speakerSilenceDBFS   = -45.0
+)
+
+// SpeakerSplit reports approximately how long each side of a stereo-split
+// recording was speaking. It is a lightweight diarisation proxy.
+type SpeakerSplit struct {
+	LocalSeconds  float64 `json:"local_seconds"`
+	RemoteSeconds float64 `json:"remote_seconds"`
+	SilentSeconds float64 `json:"silent_seconds"`
+	LocalRatio    float64 `json:"local_ratio"` // share of speaking time from the mic side
+	Dominant      string  `json:"dominant"`    // "local", "remote" or "none"
+}
+
+// DetectDominantSpeaker compares per-channel RMS energy over short windows of a
+// stereo-split recording and reports the speaking-time split between the local
+// (mic) and remote (loopback) channels.
+func (a *App) DetectDominantSpeaker(wavPath string) (SpeakerSplit, error) {
+	r, err := wav.OpenReader(wavPath)
+	if err != nil {
+		return SpeakerSplit{}, fmt.Errorf("open wav: %w", err)
+	}
+	defer r.Close()
+
+	if r.Channels() != 2 || r.BitsPerSample() != 16 {
+		return SpeakerSplit{}, fmt.Errorf("speaker detection needs a 16-bit stereo recording, got %d-bit %d-channel",
+			r.BitsPerSample(), r.Channels())
+	}
+	return speakerSplit(r, int(r.SampleRate()))
+}
+
+// speakerSplit reads interleaved stereo S16LE from src in fixed windows and
+// attributes each window to the louder channel, or to silence.
+func speakerSplit(src io.Reader, sampleRate int) (SpeakerSplit, error) {
+	frames := int(float64(sampleRate) * speakerWindowSeconds)
+	if frames < 1 {
+		return SpeakerSplit{}, errors.New("invalid sample rate")
+	}
+	buf := make([]byte, frames*2*2)
+	var samples []int16
+	var split SpeakerSplit
+
+	for {
+		n, err := io.ReadFull(src, buf)
+		if n > 0 {
+			samples = dsp.DecodeS16LE(samples, buf[:n-n%4])
+			secs := float64(len(samples)/2) / float64(sampleRate)
+			local := dsp.DBFS(dsp.ChannelRMS(samples, 2, localChannel))
+			remote := dsp.DBFS(dsp.ChannelRMS(samples, 2, remoteChannel))
+			switch {
+			case local < speakerSilenceDBFS && remote < speakerSilenceDBFS:
+				split.SilentSeconds += secs
+			case local >= remote:
+				split.LocalSeconds += secs
+			default:
+				split.RemoteSeconds += secs
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return SpeakerSplit{}, fmt.Errorf("read samples: %w", err)
+		}
+	}
+
+	split.Dominant = "none"
+	if speaking := split.LocalSeconds + split.RemoteSeconds; speaking > 0 {
+		split.LocalRatio = split.LocalSeconds / speaking
+		if split.LocalSeconds >= split.RemoteSeconds {
+			split.Dominant = "local"
+		} else {
+			split.Dominant = "remote"
+		}
+	}
+	return split, nil
+}
+
+// ClippingThreshold is the fraction of full-scale samples above which a
+// recording is flagged as clipped (0.1%).
+const ClippingThreshold = 0.001
+
+// DetectClipping returns the fraction of samples in a 16-bit recording that sit
+// at full scale (±32767, or -32768).
+func (a *App) DetectClipping(wavPath string) (float64, error) {
+	r, err := wav.OpenReader(wavPath)
+	if err != nil {
+		return 0, fmt.Errorf("open wav: %w", err)
+	}
+	defer r.Close()
+
+	if r.BitsPerSample() != 16 {
+		return 0, fmt.Errorf("clipping detection needs 16-bit PCM, got %d-bit", r.BitsPerSample())
+	}
+	return clippingFraction(r)
+}
+
+// ListClippedRecordings returns recordings in OutDir whose clipping fraction
+// exceeds ClippingThreshold, so the user can re-record or normalise them.
+func (a *App) ListClippedRecordings() ([]RecordingInfo, error) {
+	recs, err := a.listRecordings()
+	if err != nil {
+		return nil, err
+	}
+	var clipped []RecordingInfo
+	for _, rec := range recs {
+		if rec.BitsPerSample != 16 {
+			continue
+		}
+		frac, err := a.DetectClipping(rec.Path)
+		if err != nil {
+			continue
+		}
+		if frac > ClippingThreshold {
+			clipped = append(clipped, rec)
+		}
+	}
+	return clipped, nil
+}
+
+// clippingFraction reads S16LE samples from src and returns the share at full scale.
+func clippingFraction(src io.Reader) (float64, error) {
+	buf := make([]byte, 64*1024)
+	var samples []int16
+	var total, clipped int64
+
+	for {
+		n, err := io.ReadFull(src, buf)
+		if n > 0 {
+			samples = dsp.DecodeS16LE(samples, buf[:n])
+			for _, s := range samples {
+				if s >= 32767 || s <= -32767 {
+					clipped++
+				}
+			}
+			total += int64(len(samples))
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("read samples: %w", err)
+		}
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	return float64(clipped) / float64(total), nil
+}
+
+// SilenceThresholdDBFS is the level below which a recording counts as silent.
+// It sits well under speakerSilenceDBFS so quiet speech isn't mistaken for silence.
+const SilenceThresholdDBFS = -60.0
+
+// IsSilentRecording reports whether a 16-bit recording is effectively silent:
+// no 100 ms window has an RMS level above SilenceThresholdDBFS. Use it to skip
+// transcribing captures of a muted source or the wrong device.
+func (a *App) IsSilentRecording(wavPath string) (bool, error) {
+	r, err := wav.OpenReader(wavPath)
+	if err != nil {
+		return false, fmt.Errorf("open wav: %w", err)
+	}
+	defer r.Close()
+
+	if r.BitsPerSample() != 16 {
+		return false, fmt.Errorf("silence detection needs 16-bit PCM, got %d-bit", r.BitsPerSample())
+	}
+	peak, err := peakWindowRMS(r, int(r.SampleRate())*int(r.Channels()))
+	if err != nil {
+		return false, err
+	}
+	return dsp.DBFS(peak) < SilenceThresholdDBFS, nil
+}
+
+// peakWindowRMS returns the highest normalised RMS over speakerWindowSeconds
+// windows of S16LE samples from src, with samplesPerSecond counting all channels.
+func peakWindowRMS(src io.Reader, samplesPerSecond int) (float64, error) {
+	window := max(int(float64(samplesPerSecond)*speakerWindowSeconds), 1)
+	buf := make([]byte, window*2)
+	var samples []int16
+	var peak float64
+	for {
+		n, err := io.ReadFull(src, buf)
+		if n >= 2 {
+			samples = dsp.DecodeS16LE(samples, buf[:n])
+			peak = max(peak, dsp.RMS(samples))
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("read samples: %w", err)
+		}
+	}
+	return peak, nil
+}
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// segment is a stretch of a generated stereo recording with a 440 Hz tone
+// at the given peak amplitude (0-1) on each channel.
+type segment struct {
+	seconds, left, right float64
+}
+
+func stereoSegments(sampleRate int, segs ...segment) []int16 {
+	var out []int16
+	for _, s := range segs {
+		l := sine(sampleRate, 1, s.seconds, s.left)
+		r := sine(sampleRate, 1, s.seconds, s.right)
+		for i := range l {
+			out = append(out, l[i], r[i])
+		}
+	}
+	return out
+}
+
+func TestDetectDominantSpeaker(t *testing.T) {
+	const rate = 16000
+	tests := []struct {
+		name                  string
+		segs                  []segment
+		local, remote, silent float64
+		dominant              string
+	}{
+		{"local only", []segment{{1, 0.5, 0}}, 1, 0, 0, "local"},
+		{"remote talks longer", []segment{{1, 0.5, 0.01}, {2, 0.01, 0.5}}, 1, 2, 0, "remote"},
+		{"louder side wins", []segment{{1, 0.5, 0.2}}, 1, 0, 0, "local"},
+		{"with silence", []segment{{0.5, 0, 0}, {1.5, 0, 0.3}}, 0, 1.5, 0.5, "remote"},
+		{"silent", []segment{{1, 0.001, 0.001}}, 0, 0, 1, "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t, UISettings{})
+			path := filepath.Join(t.TempDir(), "call.wav")
+			writeTestWAV(t, path, rate, 2, stereoSegments(rate, tt.segs...))
+
+			got, err := a.DetectDominantSpeaker(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			near := func(a, b float64) bool { return math.Abs(a-b) < 0.01 }
+			if !near(got.LocalSeconds, tt.local) || !near(got.RemoteSeconds, tt.remote) || !near(got.SilentSeconds, tt.silent) {
+				t.Errorf("local/remote/silent = %.2f/%.2f/%.2f, want %.2f/%.2f/%.2f",
+					got.LocalSeconds, got.RemoteSeconds, got.SilentSeconds, tt.local, tt.remote, tt.silent)
+			}
+			if got.Dominant != tt.dominant {
+				t.Errorf("dominant = %q, want %q", got.Dominant, tt.dominant)
+			}
+			if speaking := tt.local + tt.remote; speaking > 0 && !near(got.LocalRatio, tt.local/speaking) {
+				t.Errorf("local ratio = %.3f, want %.3f", got.LocalRatio, tt.local/speaking)
+			}
+		})
+	}
+}
+
+func TestDetectDominantSpeakerNeedsStereo(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	path := filepath.Join(t.TempDir(), "mono.wav")
+	writeTestWAV(t, path, 16000, 1, sine(16000, 1, 0.5, 0.5))
+	if _, err := a.DetectDominantSpeaker(path); err == nil {
+		t.Error("mono recording accepted")
+	}
+}
+
+func TestDetectClipping(t *testing.T) {
+	// clipped returns a second of quiet tone with every step-th sample at
+	// full scale, alternating polarity.
+	clipped := func(step int) []int16 {
+		s := sine(16000, 1, 1, 0.25)
+		for i := 0; i < len(s); i += step {
+			s[i] = 32767
+			if (i/step)%2 == 1 {
+				s[i] = -32768
+			}
+		}
+		return s
+	}
+	tests := []struct {
+		name    string
+		samples []int16
+		want    float64
+		flagged bool
+	}{
+		{"clean", sine(16000, 1, 1, 0.5), 0, false},
+		{"near full scale", sine(16000, 1, 1, 0.99), 0, false},
+		{"square wave", clipped(1), 1, true},
+		{"one percent", clipped(100), 0.01, true},
+		{"below threshold", clipped(2000), 0.0005, false},
+		{"empty", nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t, UISettings{})
+			path := filepath.Join(t.TempDir(), "rec.wav")
+			writeTestWAV(t, path, 16000, 1, tt.samples)
+
+			got, err := a.DetectClipping(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("fraction = %v, want %v", got, tt.want)
+			}
+			if flagged := got > ClippingThreshold; flagged != tt.flagged {
+				t.Errorf("flagged = %v, want %v", flagged, tt.flagged)
+			}
+		})
+	}
+}
+
+func TestIsSilentRecording(t *testing.T) {
+	// burst is quiet hiss with a tenth of a second of tone in the middle
+	burst := slices.Concat(sine(16000, 1, 2, 0.0002), sine(16000, 1, 0.1, 0.1), sine(16000, 1, 2, 0.0002))
+	tests := []struct {
+		name     string
+		channels int
+		samples  []int16
+		want     bool
+	}{
+		{"digital silence", 1, make([]int16, 16000), true},
+		{"empty", 1, nil, true},
+		{"hum below threshold", 1, sine(16000, 1, 3, 0.0005), true}, // about -69 dBFS
+		{"quiet speech level", 1, sine(16000, 1, 3, 0.01), false},   // about -43 dBFS
+		{"short burst", 1, burst, false},
+		{"one channel live", 2, stereoSegments(16000, segment{2, 0, 0.01}), false},
+		{"stereo silence", 2, stereoSegments(16000, segment{2, 0.0002, 0.0002}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t, UISettings{})
+			path := filepath.Join(t.TempDir(), "rec.wav")
+			writeTestWAV(t, path, 16000, tt.channels, tt.samples)
+
+			got, err := a.IsSilentRecording(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("silent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("not a wav", func(t *testing.T) {
+		a := newTestApp(t, UISettings{})
+		path := filepath.Join(t.TempDir(), "rec.wav")
+		if err := os.WriteFile(path, []byte("not audio"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := a.IsSilentRecording(path); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
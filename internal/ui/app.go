@@ -8,17 +8,22 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"blackbox/internal/audio"
+	"blackbox/internal/dsp"
 	"blackbox/internal/execx"
+	"blackbox/internal/pathx"
 	"blackbox/internal/wav"
 
 	wruntime "github.com/wailsapp/wails/v2/pkg/runtime"
@@ -29,6 +34,22 @@ type PromptConfig struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Prompt      string `json:"prompt"`
+
+	// Set when listing; not persisted to prompt files
+	Key     string `json:"key,omitempty"`      // cache key (filename without .json)
+	BuiltIn bool   `json:"built_in,omitempty"` // true for the default prompts
+}
+
+// builtinPrompts are the default prompt keys, which custom files cannot override.
+var builtinPrompts = []string{"meeting", "dictation"}
+
+func isBuiltinPrompt(key string) bool {
+	for _, k := range builtinPrompts {
+		if k == key {
+			return true
+		}
+	}
+	return false
 }
 
 // App exposes methods to the Wails frontend.
@@ -37,16 +58,31 @@ type App struct {
 
 	mu          sync.Mutex
 	recording   bool
+	paused      bool
 	dictation   bool
 	rec         *audio.Recorder
 	mic         *audio.MicRecorder
-	writer      *wav.Writer
+	writer      wav.Encoder
+	micWriter   wav.Encoder // multitrack only
 	runErrCh    chan error
 	ctx         context.Context
 	cancel      context.CancelFunc
 	flushTicker *time.Ticker
 	wavPath     string
 
+	// Transcription speed history for ETAs
+	transcribeSpeed speedTracker
+
+	// In-flight work, for CancelTranscribe/CancelSummarise
+	transcribeOps cancelSet
+	summariseOps  cancelSet
+
+	// Recently played recordings, for GetAudioDataURL
+	audioCache audioCache
+
+	// Loudness measurements, for MeasureLoudness
+	loudness loudnessCache
+
 	// Llama server management
 	llamaServer *exec.Cmd
 	llamaMu     sync.Mutex
@@ -56,7 +92,8 @@ type App struct {
 	promptCache    map[string]PromptConfig
 	promptMu       sync.RWMutex
 
-	uiCtx context.Context
+	uiCtx       context.Context
+	startupOnce sync.Once
 }
 
 func NewApp(settingsPath string) (*App, error) {
@@ -65,7 +102,7 @@ func NewApp(settingsPath string) (*App, error) {
 		return nil, err
 	}
 	s := store.Get()
-	if err := os.MkdirAll(s.OutDir, 0755); err != nil {
+	if err := os.MkdirAll(pathx.Long(s.OutDir), 0755); err != nil {
 		return nil, err
 	}
 
@@ -97,9 +134,14 @@ func (a *App) SaveSettings(jsonStr string) (UISettings, error) {
 	if cfg.OutDir == "" {
 		cfg.OutDir = "./out"
 	}
-	if err := os.MkdirAll(cfg.OutDir, 0755); err != nil {
+	if err := os.MkdirAll(pathx.Long(cfg.OutDir), 0755); err != nil {
 		return UISettings{}, err
 	}
+	if cfg.TranscriptDir != "" {
+		if err := os.MkdirAll(pathx.Long(cfg.TranscriptDir), 0755); err != nil {
+			return UISettings{}, err
+		}
+	}
 	if err := a.settings.Save(cfg); err != nil {
 		return UISettings{}, err
 	}
@@ -110,20 +152,35 @@ func (a *App) SaveSettings(jsonStr string) (UISettings, error) {
 
 // GetAvailablePrompts returns a list of available prompt configurations
 func (a *App) GetAvailablePrompts() ([]PromptConfig, error) {
-	a.promptMu.RLock()
-	defer a.promptMu.RUnlock()
-
-	// Load custom prompts from config directory
-	if err := a.loadCustomPrompts(); err != nil {
+	// Load custom prompts from config directory outside the lock, then merge
+	custom, err := loadCustomPrompts()
+	if err != nil {
 		// Log error but don't fail - custom prompts are optional
 		fmt.Printf("Warning: failed to load custom prompts: %v\n", err)
 	}
 
+	a.promptMu.Lock()
+	defer a.promptMu.Unlock()
+	for name, config := range custom {
+		a.promptCache[name] = config
+	}
+
 	var prompts []PromptConfig
 	for _, prompt := range a.promptCache {
 		prompts = append(prompts, prompt)
 	}
 
+	// Built-ins first, then by name, so the dropdown order is stable
+	sort.Slice(prompts, func(i, j int) bool {
+		if prompts[i].BuiltIn != prompts[j].BuiltIn {
+			return prompts[i].BuiltIn
+		}
+		if prompts[i].Name != prompts[j].Name {
+			return strings.ToLower(prompts[i].Name) < strings.ToLower(prompts[j].Name)
+		}
+		return prompts[i].Key < prompts[j].Key
+	})
+
 	return prompts, nil
 }
 
@@ -174,7 +231,8 @@ func (a *App) SaveCustomPrompt(config PromptConfig) error {
 
 	// Save to file
 	filename := fmt.Sprintf("./config/%s.json", config.Name)
-	data, err := json.MarshalIndent(config, "", "  ")
+	fileConfig := PromptConfig{Name: config.Name, Description: config.Description, Prompt: config.Prompt}
+	data, err := json.MarshalIndent(fileConfig, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal prompt config: %w", err)
 	}
@@ -184,8 +242,10 @@ func (a *App) SaveCustomPrompt(config PromptConfig) error {
 	}
 
 	// Update cache
+	fileConfig.Key = config.Name
+	fileConfig.BuiltIn = isBuiltinPrompt(config.Name)
 	a.promptMu.Lock()
-	a.promptCache[config.Name] = config
+	a.promptCache[config.Name] = fileConfig
 	a.promptMu.Unlock()
 
 	return nil
@@ -193,9 +253,7 @@ func (a *App) SaveCustomPrompt(config PromptConfig) error {
 
 // loadDefaultPrompts loads the built-in prompt configurations
 func (a *App) loadDefaultPrompts() error {
-	defaultPrompts := []string{"meeting", "dictation"}
-
-	for _, promptName := range defaultPrompts {
+	for _, promptName := range builtinPrompts {
 		filename := fmt.Sprintf("./config/%s.json", promptName)
 		data, err := os.ReadFile(filename)
 		if err != nil {
@@ -206,6 +264,8 @@ func (a *App) loadDefaultPrompts() error {
 		if err := json.Unmarshal(data, &config); err != nil {
 			return fmt.Errorf("failed to parse %s: %w", filename, err)
 		}
+		config.Key = promptName
+		config.BuiltIn = true
 
 		a.promptCache[promptName] = config
 	}
@@ -213,21 +273,26 @@ func (a *App) loadDefaultPrompts() error {
 	return nil
 }
 
-// loadCustomPrompts loads custom prompt files from the config directory
-func (a *App) loadCustomPrompts() error {
+// loadCustomPrompts reads custom prompt files from the config directory, keyed
+// by file name. It doesn't touch the prompt cache, so it needs no lock.
+func loadCustomPrompts() (map[string]PromptConfig, error) {
 	configDir := "./config"
 	entries, err := os.ReadDir(configDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	prompts := make(map[string]PromptConfig)
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
 			continue
 		}
 
+		// Use filename without extension as key
+		promptName := strings.TrimSuffix(entry.Name(), ".json")
+
 		// Skip default prompts
-		if entry.Name() == "meeting.json" || entry.Name() == "dictation.json" {
+		if isBuiltinPrompt(promptName) {
 			continue
 		}
 
@@ -241,13 +306,13 @@ func (a *App) loadCustomPrompts() error {
 		if err := json.Unmarshal(data, &config); err != nil {
 			continue // Skip files that can't be parsed
 		}
+		config.Key = promptName
+		config.BuiltIn = false
 
-		// Use filename without extension as key
-		promptName := strings.TrimSuffix(entry.Name(), ".json")
-		a.promptCache[promptName] = config
+		prompts[promptName] = config
 	}
 
-	return nil
+	return prompts, nil
 }
 
 // --- Recording API ---
@@ -258,6 +323,60 @@ func (a *App) IsRecording() bool {
 	return a.recording
 }
 
+// IsPaused reports whether the active recording is paused.
+func (a *App) IsPaused() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.paused
+}
+
+// PauseRecording stops writing captured audio to the file. Devices keep
+// running so ResumeRecording continues the same file without a gap in setup.
+func (a *App) PauseRecording() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.recording {
+		return errors.New("not recording")
+	}
+	if a.paused {
+		return errors.New("already paused")
+	}
+	a.paused = true
+	return nil
+}
+
+// ResumeRecording resumes writing captured audio after PauseRecording.
+func (a *App) ResumeRecording() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.recording {
+		return errors.New("not recording")
+	}
+	if !a.paused {
+		return errors.New("not paused")
+	}
+	a.paused = false
+	return nil
+}
+
+// GetCaptureMetrics returns callback-to-write latency for the active recording,
+// keyed by source ("loopback", "microphone").
+func (a *App) GetCaptureMetrics() (map[string]audio.CaptureMetrics, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.recording {
+		return nil, errors.New("not recording")
+	}
+	metrics := make(map[string]audio.CaptureMetrics)
+	if a.rec != nil {
+		metrics["loopback"] = a.rec.CaptureMetrics()
+	}
+	if a.mic != nil {
+		metrics["microphone"] = a.mic.CaptureMetrics()
+	}
+	return metrics, nil
+}
+
 // StartRecording starts loopback (and optional mic) capture and writes to a new WAV file under OutDir.
 // Returns the path to the WAV file that will be written.
 func (a *App) StartRecording(withMic bool) (string, error) {
@@ -275,6 +394,7 @@ func (a *App) StopRecording() (string, error) {
 	rec := a.rec
 	mic := a.mic
 	writer := a.writer
+	micWriter := a.micWriter
 	flushTicker := a.flushTicker
 	runErrCh := a.runErrCh
 	cancel := a.cancel
@@ -283,11 +403,13 @@ func (a *App) StopRecording() (string, error) {
 	a.rec = nil
 	a.mic = nil
 	a.writer = nil
+	a.micWriter = nil
 	a.flushTicker = nil
 	a.runErrCh = nil
 	a.cancel = nil
 	a.ctx = nil
 	a.recording = false
+	a.paused = false
 	a.wavPath = ""
 	a.mu.Unlock()
 
@@ -316,6 +438,12 @@ func (a *App) StopRecording() (string, error) {
 	if err := writer.Close(); err != nil {
 		return wavPath, fmt.Errorf("finalize wav: %w", err)
 	}
+	if micWriter != nil {
+		_ = micWriter.Flush()
+		if err := micWriter.Close(); err != nil {
+			return wavPath, fmt.Errorf("finalize mic track: %w", err)
+		}
+	}
 	if runErr != nil && !errors.Is(runErr, context.Canceled) {
 		return wavPath, runErr
 	}
@@ -323,28 +451,77 @@ func (a *App) StopRecording() (string, error) {
 }
 
 // Transcribe runs whisper.cpp on the selected WAV and returns the produced .txt path.
+// The model comes from the WhisperModel setting.
 func (a *App) Transcribe(wavPath string) (string, error) {
+	return a.TranscribeWithModel(wavPath, "")
+}
+
+// TranscribeWithModel is Transcribe with an explicit whisper model (a path, or
+// a file name in the models directory). An empty model uses the WhisperModel setting.
+func (a *App) TranscribeWithModel(wavPath, model string) (string, error) {
+	return a.transcribe(wavPath, model, 0)
+}
+
+// transcribe is TranscribeWithModel with a whisper thread count; 0 uses
+// whisper's default.
+func (a *App) transcribe(wavPath, model string, threads int) (string, error) {
 	if strings.TrimSpace(wavPath) == "" {
 		return "", errors.New("wav path required")
 	}
 	cfg := a.settings.Get()
-	outDir := cfg.OutDir
+	outDir := cfg.transcriptDir()
 	if outDir == "" {
 		outDir = "./out"
 	}
-	if err := os.MkdirAll(outDir, 0755); err != nil {
+	if err := os.MkdirAll(pathx.Long(outDir), 0755); err != nil {
 		return "", err
 	}
+	ctx, done := a.transcribeOps.begin()
+	defer done()
 
 	whisperBin := getenvDefault("LOOPBACK_NOTES_WHISPER_BIN", "./whisper-bin/whisper-cli.exe")
-	modelDir := getenvDefault("LOOPBACK_NOTES_MODELS", "./models")
-	modelPath := filepath.Join(modelDir, "ggml-base.en.bin")
+	if model == "" {
+		model = cfg.WhisperModel
+	}
+	modelPath := whisperModelPath(model)
+	source := wavPath
+
+	// Compressed recordings are decoded to a temporary WAV for whisper
+	if !isWAVFile(wavPath) {
+		decoded, cleanup, err := decodeForTranscription(wavPath)
+		if err != nil {
+			return "", fmt.Errorf("decode for transcription: %w", err)
+		}
+		defer cleanup()
+		wavPath = decoded
+	}
+
+	// Higher-rate or stereo recordings get a temporary 16 kHz mono copy
+	if !cfg.SkipWhisperConversion {
+		converted, cleanup, err := convertForWhisper(wavPath)
+		if err != nil {
+			return "", fmt.Errorf("prepare for transcription: %w", err)
+		}
+		defer cleanup()
+		wavPath = converted
+	}
 
-	txtPath, err := execx.RunWhisper(whisperBin, modelPath, wavPath, outDir, "en", 0, "")
+	onProgress := func(pct float64, segment string) {
+		if a.uiCtx != nil {
+			wruntime.EventsEmit(a.uiCtx, "whisperProgress", WhisperProgress{Source: source, Percent: pct, Segment: segment})
+		}
+	}
+	started := time.Now()
+	// SRT/VTT and JSON land next to the .txt; see GetTranscriptSegments and
+	// GetTranscriptConfidence
+	opts := execx.WhisperOptions{Subtitles: cfg.Subtitles, Confidence: cfg.Confidence}
+	lang := recordingLanguage(source, cfg)
+	out, err := execx.RunWhisperWithOptions(ctx, whisperBin, modelPath, wavPath, outDir, lang, threads, "", opts, onProgress)
 	if err != nil {
-		return "", err
+		return "", canceledErr(ctx, err)
 	}
-	return txtPath, nil
+	a.transcribeSpeed.observe(wavDuration(wavPath), time.Since(started))
+	return out.Txt, nil
 }
 
 // Summarise reads configs/llm.json and sends the transcript to OpenAI or local AI for summarisation.
@@ -352,17 +529,20 @@ func (a *App) Summarise(txtPath string) (string, error) {
 	if strings.TrimSpace(txtPath) == "" {
 		return "", errors.New("txt path required")
 	}
-	if _, err := os.Stat(txtPath); err != nil {
+	if _, err := os.Stat(pathx.Long(txtPath)); err != nil {
 		return "", err
 	}
 
 	uiCfg := a.settings.Get()
+	ctx, done := a.summariseOps.begin()
+	defer done()
 
 	// Read the transcript file
-	transcript, err := os.ReadFile(txtPath)
+	transcriptData, err := os.ReadFile(pathx.Long(txtPath))
 	if err != nil {
 		return "", fmt.Errorf("failed to read transcript: %w", err)
 	}
+	transcript, truncated := trimToTokenBudget(stripAppendedSummary(string(transcriptData)), uiCfg.SummaryTokenBudget)
 
 	// Get the selected prompt configuration
 	promptConfig, err := a.GetPromptConfig(a.GetSelectedPrompt())
@@ -371,12 +551,30 @@ func (a *App) Summarise(txtPath string) (string, error) {
 	}
 	prompt := promptConfig.Prompt
 
+	base := strings.TrimSuffix(filepath.Base(txtPath), filepath.Ext(txtPath))
+	outputPath := filepath.Join(uiCfg.transcriptDir(), base+"_summary.txt")
+
+	// Optionally let the model build on the summary from a previous run
+	var previous string
+	if uiCfg.IncludePreviousSummary {
+		previous = previousSummary(outputPath, string(transcriptData))
+	}
+
 	var summary string
+	redacted := 0
+	parts := 1
 
 	if uiCfg.UseLocalAI {
+		// Fail early with a setup problem rather than deep inside the request
+		if err := a.ValidateLocalAISetup(); err != nil {
+			return "", fmt.Errorf("local AI setup: %w", err)
+		}
 		// Use local AI (llama.cpp) - load from local.json
-		summary, err = a.summariseWithLocalAI(string(transcript), prompt)
+		summary, parts, err = a.summariseWithLocalAI(ctx, transcript, prompt, previous, uiCfg.LlamaContext, a.summaryChunkEmitter(txtPath))
 		if err != nil {
+			if ctx.Err() != nil {
+				return "", canceledErr(ctx, err)
+			}
 			return "", fmt.Errorf("local AI summarisation failed: %w", err)
 		}
 	} else {
@@ -390,88 +588,207 @@ func (a *App) Summarise(txtPath string) (string, error) {
 			return "", fmt.Errorf("api_key is required in remote config")
 		}
 
-		// Prepare the chat request
-		request := chatRequest{
-			Model: cfg.Model,
-			Messages: []chatMessage{
-				{
-					Role:    "system",
-					Content: prompt,
-				},
-				{
-					Role:    "user",
-					Content: string(transcript),
-				},
-			},
-			MaxTokens: 2000,
-		}
-
-		// Make the API request
-		summary, err = a.makeOpenAIRequest(cfg.BaseURL, cfg.APIKey, request)
+		// Scrub the transcript (and any earlier summary) before it leaves the machine
+		if uiCfg.RedactRemote {
+			var n int
+			if transcript, redacted, err = redactTranscript(transcript, uiCfg.RedactPatterns); err != nil {
+				return "", err
+			}
+			if previous, n, err = redactTranscript(previous, uiCfg.RedactPatterns); err != nil {
+				return "", err
+			}
+			redacted += n
+		}
+
+		complete := providerComplete(ctx, newProvider(cfg, summaryMaxTokens))
+
+		// Long transcripts are summarised in parts, then combined
+		chunks := chunkTranscript(transcript, uiCfg.SummaryChunkTokens)
+		parts = len(chunks)
+		summary, err = summariseChunks(complete, prompt, chunks, uiCfg.SummaryChunkTokens, previous, a.summaryChunkEmitter(txtPath))
 		if err != nil {
+			if ctx.Err() != nil {
+				return "", canceledErr(ctx, err)
+			}
 			return "", fmt.Errorf("API request failed: %w", err)
 		}
 	}
 
-	// Write summary to output file
-	outputPath := strings.TrimSuffix(txtPath, filepath.Ext(txtPath)) + "_summary.txt"
-	if err := os.WriteFile(outputPath, []byte(summary), 0644); err != nil {
-		return "", fmt.Errorf("failed to write summary: %w", err)
+	if parts > 1 {
+		summary = chunkedSummaryNote(parts) + summary
+	}
+	if previous != "" {
+		summary = previousSummaryNote + summary
+	}
+	if redacted > 0 {
+		summary = redactionNote(redacted) + summary
+	}
+	if truncated {
+		summary = truncationNote(uiCfg.SummaryTokenBudget) + summary
+	}
+
+	// Write summary to output file(s); separate summaries live in TranscriptDir
+	if uiCfg.SummaryOutput != SummaryOutputAppend {
+		if err := os.MkdirAll(pathx.Long(filepath.Dir(outputPath)), 0755); err != nil {
+			return "", fmt.Errorf("failed to create summary directory: %w", err)
+		}
+		if err := os.WriteFile(pathx.Long(outputPath), []byte(summary), 0644); err != nil {
+			return "", fmt.Errorf("failed to write summary: %w", err)
+		}
+	}
+	if uiCfg.SummaryOutput == SummaryOutputAppend || uiCfg.SummaryOutput == SummaryOutputBoth {
+		if err := appendSummaryToTranscript(txtPath, summary); err != nil {
+			return "", err
+		}
+		if uiCfg.SummaryOutput == SummaryOutputAppend {
+			outputPath = txtPath
+		}
 	}
 
 	return fmt.Sprintf("Summary written to: %s\n\n--- Summary ---\n%s", outputPath, summary), nil
 }
 
+// transcriptSummaryDelimiter separates the transcript from an appended summary.
+const transcriptSummaryDelimiter = "\n\n===== SUMMARY =====\n\n"
+
+// appendSummaryToTranscript appends a delimited summary section to the transcript file,
+// replacing any summary appended by a previous run.
+func appendSummaryToTranscript(txtPath, summary string) error {
+	data, err := os.ReadFile(pathx.Long(txtPath))
+	if err != nil {
+		return fmt.Errorf("failed to read transcript for append: %w", err)
+	}
+	content := stripAppendedSummary(string(data)) + transcriptSummaryDelimiter + summary + "\n"
+	if err := os.WriteFile(pathx.Long(txtPath), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to append summary: %w", err)
+	}
+	return nil
+}
+
+// stripAppendedSummary returns the transcript text without an appended summary section.
+func stripAppendedSummary(text string) string {
+	if i := strings.Index(text, transcriptSummaryDelimiter); i >= 0 {
+		return text[:i]
+	}
+	return text
+}
+
 // summariseWithLocalAI uses the local llama-server for summarisation
-func (a *App) summariseWithLocalAI(transcript, prompt string) (string, error) {
+// Transcripts too long for contextTokens are summarised in parts and combined;
+// the number of parts is returned. onDelta receives streamed pieces of the
+// final summary as they arrive.
+func (a *App) summariseWithLocalAI(ctx context.Context, transcript, prompt, previous string, contextTokens int, onDelta func(string)) (string, int, error) {
 	// Ensure llama-server is running
 	if !a.isLlamaServerRunning() {
 		if err := a.startLlamaServer(); err != nil {
-			return "", fmt.Errorf("failed to start llama-server: %w", err)
+			return "", 0, fmt.Errorf("failed to start llama-server: %w", err)
 		}
 	}
+	// Shutdown llama-server once all parts are done, including on error
+	defer a.stopLlamaServer()
 
 	// Load API key from local.json for client authentication
 	cfg, err := a.loadLLMConfig("./configs/local.json")
 	if err != nil {
-		return "", fmt.Errorf("failed to load local config: %w", err)
-	}
-
-	// Prepare the chat request for local AI
-	request := chatRequest{
-		Model: "local", // Model name doesn't matter for local AI
-		Messages: []chatMessage{
-			{
-				Role:    "system",
-				Content: prompt,
-			},
-			{
-				Role:    "user",
-				Content: transcript,
-			},
-		},
-		MaxTokens: 2000,
+		return "", 0, fmt.Errorf("failed to load local config: %w", err)
 	}
 
-	// Make the request to local llama-server using API key from local.json
-	summary, err := a.makeOpenAIRequest("http://127.0.0.1:8080", cfg.APIKey, request)
+	// Make requests to local llama-server using API key from local.json
+	// Model name doesn't matter for local AI
+	localCfg := &llmConfig{BaseURL: "http://127.0.0.1:8080", APIKey: cfg.APIKey, Model: "local"}
+	complete := providerComplete(ctx, &OpenAIProvider{cfg: localCfg, maxTokens: summaryMaxTokens})
+
+	chunkTokens := localChunkTokens(contextTokens, prompt, previous)
+	chunks := chunkTranscript(transcript, chunkTokens)
+	summary, err := summariseChunks(complete, prompt, chunks, chunkTokens, previous, onDelta)
 	if err != nil {
-		// Shutdown server on error
-		a.stopLlamaServer()
-		return "", fmt.Errorf("local AI request failed: %w", err)
+		return "", 0, fmt.Errorf("local AI request failed: %w", err)
 	}
-
-	// Shutdown llama-server after successful summarisation
-	a.stopLlamaServer()
-
-	return summary, nil
+	return summary, len(chunks), nil
 }
 
 // Helper: load LLM config shared with CLI semantics
 type llmConfig struct {
-	BaseURL string `json:"base_url"`
-	APIKey  string `json:"api_key"`
-	Model   string `json:"model"`
+	Provider string `json:"provider,omitempty"` // "openai" (default), "azure" or "anthropic"
+	BaseURL  string `json:"base_url"`
+	APIKey   string `json:"api_key"`
+	Model    string `json:"model"`
+
+	// MaxRetries is how often 429/500/502/503 responses are retried with
+	// backoff. 0 uses the default (3); negative disables retries.
+	MaxRetries int `json:"max_retries,omitempty"`
+
+	// Azure OpenAI settings, used when Provider is "azure"
+	AzureResource   string `json:"azure_resource,omitempty"`
+	AzureDeployment string `json:"azure_deployment,omitempty"`
+	AzureAPIVersion string `json:"azure_api_version,omitempty"`
+}
+
+const (
+	providerOpenAI    = "openai"
+	providerAzure     = "azure"
+	providerAnthropic = "anthropic"
+
+	defaultAnthropicBaseURL = "https://api.anthropic.com"
+
+	defaultAzureAPIVersion = "2024-10-21"
+)
+
+func (c *llmConfig) isAzure() bool {
+	return strings.EqualFold(strings.TrimSpace(c.Provider), providerAzure)
+}
+
+func (c *llmConfig) isAnthropic() bool {
+	return strings.EqualFold(strings.TrimSpace(c.Provider), providerAnthropic)
+}
+
+// messagesURL returns the Anthropic Messages endpoint. base_url may be given
+// with or without the /v1 suffix.
+func (c *llmConfig) messagesURL() string {
+	base := strings.TrimRight(c.BaseURL, "/")
+	if base == "" {
+		base = defaultAnthropicBaseURL
+	}
+	if !strings.HasSuffix(base, "/v1") {
+		base += "/v1"
+	}
+	return base + "/messages"
+}
+
+// endpointURL returns the URL requests are sent to, for error messages.
+func (c *llmConfig) endpointURL() string {
+	if c.isAnthropic() {
+		return c.messagesURL()
+	}
+	return c.chatCompletionsURL()
+}
+
+// chatCompletionsURL returns the chat completions endpoint for the configured provider.
+// Azure uses /openai/deployments/{deployment}/chat/completions?api-version=... on the
+// resource host (or base_url, if set, for custom domains).
+func (c *llmConfig) chatCompletionsURL() string {
+	if !c.isAzure() {
+		return c.BaseURL + "/chat/completions"
+	}
+	endpoint := strings.TrimRight(c.BaseURL, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.openai.azure.com", c.AzureResource)
+	}
+	apiVersion := c.AzureAPIVersion
+	if apiVersion == "" {
+		apiVersion = defaultAzureAPIVersion
+	}
+	return fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		endpoint, url.PathEscape(c.AzureDeployment), url.QueryEscape(apiVersion))
+}
+
+// setAuthHeader sets the provider-specific authentication header.
+func (c *llmConfig) setAuthHeader(req *http.Request) {
+	if c.isAzure() {
+		req.Header.Set("api-key", c.APIKey)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 }
 
 // Chat API types
@@ -485,6 +802,7 @@ type chatRequest struct {
 	Messages    []chatMessage `json:"messages"`
 	MaxTokens   int           `json:"max_completion_tokens,omitempty"`
 	Temperature float64       `json:"temperature,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
 }
 
 type chatResponse struct {
@@ -498,7 +816,18 @@ type chatResponse struct {
 	} `json:"error,omitempty"`
 }
 
-func (a *App) makeOpenAIRequest(baseURL, apiKey string, request chatRequest) (string, error) {
+// apiStatusError is returned by makeOpenAIRequest for non-200 responses.
+type apiStatusError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // from the Retry-After header, 0 if absent
+}
+
+func (e *apiStatusError) Error() string {
+	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Body)
+}
+
+func makeOpenAIRequest(ctx context.Context, cfg *llmConfig, request chatRequest) (string, error) {
 	// Prepare the request body
 	jsonData, err := json.Marshal(request)
 	if err != nil {
@@ -506,15 +835,14 @@ func (a *App) makeOpenAIRequest(baseURL, apiKey string, request chatRequest) (st
 	}
 
 	// Create HTTP request
-	url := baseURL + "/chat/completions"
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", cfg.chatCompletionsURL(), bytes.NewBuffer(jsonData))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+	cfg.setAuthHeader(req)
 
 	// Make the request
 	client := &http.Client{Timeout: 360 * time.Second}
@@ -532,10 +860,14 @@ func (a *App) makeOpenAIRequest(baseURL, apiKey string, request chatRequest) (st
 
 	// Check HTTP status
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return "", newAPIStatusError(resp, body)
 	}
 
-	// Parse response
+	return parseChatResponse(body)
+}
+
+// parseChatResponse extracts the message content from a chat completion body.
+func parseChatResponse(body []byte) (string, error) {
 	var chatResp chatResponse
 	if err := json.Unmarshal(body, &chatResp); err != nil {
 		return "", fmt.Errorf("failed to parse response: %w", err)
@@ -563,8 +895,23 @@ func (a *App) loadLLMConfig(path string) (*llmConfig, error) {
 	if err := json.Unmarshal(b, &cfg); err != nil {
 		return nil, err
 	}
-	if cfg.BaseURL == "" || cfg.Model == "" || cfg.APIKey == "" {
-		return nil, fmt.Errorf("missing required fields in config")
+	switch {
+	case cfg.Provider == "" || strings.EqualFold(cfg.Provider, providerOpenAI):
+		if cfg.BaseURL == "" || cfg.Model == "" || cfg.APIKey == "" {
+			return nil, fmt.Errorf("missing required fields in config")
+		}
+	case cfg.isAnthropic():
+		// base_url defaults to the public API
+		if cfg.Model == "" || cfg.APIKey == "" {
+			return nil, fmt.Errorf("missing required anthropic fields in config (model, api_key)")
+		}
+	case cfg.isAzure():
+		// Azure routes by deployment, so model is optional
+		if (cfg.AzureResource == "" && cfg.BaseURL == "") || cfg.AzureDeployment == "" || cfg.APIKey == "" {
+			return nil, fmt.Errorf("missing required azure fields in config (azure_resource or base_url, azure_deployment, api_key)")
+		}
+	default:
+		return nil, fmt.Errorf("unknown provider %q in config", cfg.Provider)
 	}
 	return &cfg, nil
 }
@@ -576,34 +923,103 @@ func getenvDefault(k, def string) string {
 	return def
 }
 
-// mixS16Mono mixes two S16LE mono buffers with simple averaging.
-func mixS16Mono(loop, mic []byte) []byte {
+// captureSource is the part of a capture device the writer loop consumes.
+// *audio.Recorder and *audio.MicRecorder implement it.
+type captureSource interface {
+	Data() <-chan []byte
+	MarkWritten()
+	Release(b []byte)
+}
+
+// runMicLoop passes each buffer from src to write until ctx is cancelled or
+// src's data channel closes, calling flush on each tick. Buffers arriving
+// while paused reports true are discarded. Buffers are released after write
+// returns, so write must not keep them. Returns write's first error.
+func runMicLoop(ctx context.Context, src captureSource, tick <-chan time.Time, paused func() bool, write func([]byte) error, flush func()) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case b, ok := <-src.Data():
+			if !ok {
+				return nil
+			}
+			if len(b) == 0 {
+				continue
+			}
+			if paused() {
+				// Discard while paused; the devices keep running
+				src.MarkWritten()
+				src.Release(b)
+				continue
+			}
+			if err := write(b); err != nil {
+				return err
+			}
+			src.MarkWritten()
+			src.Release(b)
+		case <-tick:
+			flush()
+		}
+	}
+}
+
+// mixS16 mixes two interleaved S16LE buffers with the same channel count by
+// averaging each channel of each frame. Only whole frames are mixed; loopback
+// frames beyond the end of mic pass through unchanged so no system audio is dropped.
+func mixS16(loop, mic []byte, channels int) []byte {
 	if len(mic) == 0 {
 		return loop
 	}
-	n := len(loop)
+	if channels < 1 {
+		channels = 1
+	}
+	frameBytes := 2 * channels
+	loopLen := len(loop) - len(loop)%frameBytes
+	n := loopLen
 	if len(mic) < n {
-		n = len(mic)
-	}
-	out := make([]byte, n)
-	for i := 0; i < n; i += 2 {
-		lv := int16(int16(loop[i]) | int16(int16(loop[i+1])<<8))
-		mv := int16(int16(mic[i]) | int16(int16(mic[i+1])<<8))
-		s := int32(lv) + int32(mv)
-		s /= 2
-		if s > 32767 {
-			s = 32767
-		} else if s < -32768 {
-			s = -32768
-		}
-		out[i] = byte(uint16(int16(s)) & 0xFF)
-		out[i+1] = byte((uint16(int16(s)) >> 8) & 0xFF)
+		n = len(mic) - len(mic)%frameBytes
+	}
+	out := make([]byte, loopLen)
+	for f := 0; f < n; f += frameBytes {
+		for c := 0; c < channels; c++ {
+			i := f + 2*c
+			lv := int16(int16(loop[i]) | int16(int16(loop[i+1])<<8))
+			mv := int16(int16(mic[i]) | int16(int16(mic[i+1])<<8))
+			s := int32(lv) + int32(mv)
+			s /= 2
+			if s > 32767 {
+				s = 32767
+			} else if s < -32768 {
+				s = -32768
+			}
+			out[i] = byte(uint16(int16(s)) & 0xFF)
+			out[i+1] = byte((uint16(int16(s)) >> 8) & 0xFF)
+		}
 	}
+	copy(out[n:], loop[n:loopLen])
 	return out
 }
 
 // StartRecordingAdvanced allows selecting dictation mode (mic only) vs loopback+optional mic.
 func (a *App) StartRecordingAdvanced(withMic bool, dictation bool) (string, error) {
+	return a.startRecording(withMic, dictation, false)
+}
+
+// StartRecordingMultitrack records loopback and microphone unmixed to
+// <ts>_loopback and <ts>_mic files so they can be balanced later.
+// Returns the loopback track path; use GetTrackAudioDataURL for the mic track.
+func (a *App) StartRecordingMultitrack(withMic bool) (string, error) {
+	return a.startRecording(withMic, false, true)
+}
+
+// Capture device constructors, replaced in tests to simulate device failures.
+var (
+	newRecorder    = audio.NewRecorder
+	newMicRecorder = audio.NewMicRecorder
+)
+
+func (a *App) startRecording(withMic, dictation, multitrack bool) (string, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	if a.recording {
@@ -611,61 +1027,89 @@ func (a *App) StartRecordingAdvanced(withMic bool, dictation bool) (string, erro
 	}
 
 	cfg := a.settings.Get()
-	if err := os.MkdirAll(cfg.OutDir, 0755); err != nil {
+	if err := os.MkdirAll(pathx.Long(cfg.OutDir), 0755); err != nil {
 		return "", err
 	}
 
 	const sampleRate uint32 = 16000 // Reduced from 48000 - 16kHz is standard for speech recognition
-	const channels uint32 = 1       // Reduced from 2 - mono is sufficient for speech and cuts file size in half
 	const bits uint16 = 16
+	channels := uint32(cfg.Channels) // Mono by default - sufficient for speech and half the size of stereo
 
-	ts := time.Now().Format("20060102_150405")
-	wavPath := filepath.Join(cfg.OutDir, ts+".wav")
-	writer, err := wav.NewWriter(wavPath, sampleRate, uint16(channels), bits)
+	ts := time.Now().Format(recordingTimeLayout)
+	base := filepath.Join(cfg.OutDir, ts)
+	if multitrack {
+		base += trackSuffixLoopback
+	}
+	writer, wavPath, err := newRecordingEncoder(cfg.AudioFormat, base, sampleRate, uint16(channels), bits)
 	if err != nil {
-		return "", fmt.Errorf("open wav: %w", err)
+		return "", err
+	}
+	var micWriter wav.Encoder
+	var micPath string
+	if multitrack && withMic {
+		micWriter, micPath, err = newRecordingEncoder(cfg.AudioFormat, filepath.Join(cfg.OutDir, ts)+trackSuffixMic, sampleRate, uint16(channels), bits)
+		if err != nil {
+			_ = writer.Close()
+			_ = os.Remove(pathx.Long(wavPath))
+			return "", err
+		}
 	}
 
 	var rec *audio.Recorder
 	var mic *audio.MicRecorder
 
+	// abort rolls back a partially started recording so a failed start
+	// doesn't leave an empty file behind in OutDir.
+	abort := func(err error) (string, error) {
+		if rec != nil {
+			rec.Stop()
+		}
+		if mic != nil {
+			mic.Stop()
+		}
+		_ = writer.Close()
+		if micWriter != nil {
+			_ = micWriter.Close()
+			_ = os.Remove(pathx.Long(micPath))
+		}
+		if rmErr := os.Remove(pathx.Long(wavPath)); rmErr != nil && !os.IsNotExist(rmErr) {
+			return "", fmt.Errorf("%w (cleanup failed: %v)", err, rmErr)
+		}
+		return "", err
+	}
+
 	if dictation {
 		// Mic-only capture
-		m, err := audio.NewMicRecorder(8)
+		m, err := newMicRecorder(8)
 		if err != nil {
-			_ = writer.Close()
-			return "", fmt.Errorf("init mic: %w", err)
+			return abort(fmt.Errorf("init mic: %w", err))
 		}
 		if err := m.Start(sampleRate, channels); err != nil {
-			_ = writer.Close()
-			return "", fmt.Errorf("start mic: %w", err)
+			return abort(fmt.Errorf("start mic: %w", err))
 		}
 		mic = m
 	} else {
-		// Loopback capture (optionally mix mic)
-		r, err := audio.NewRecorder(8)
+		// Loopback capture (optionally mix mic). The mic is initialised
+		// first so a missing input device fails before loopback starts.
+		var m *audio.MicRecorder
+		if withMic {
+			if m, err = newMicRecorder(8); err != nil {
+				return abort(fmt.Errorf("init mic: %w", err))
+			}
+			mic = m
+		}
+		r, err := newRecorder(8)
 		if err != nil {
-			_ = writer.Close()
-			return "", fmt.Errorf("init recorder: %w", err)
+			return abort(fmt.Errorf("init recorder: %w", err))
 		}
-		if err := r.Start(sampleRate, channels); err != nil {
-			_ = writer.Close()
-			return "", fmt.Errorf("start recorder: %w", err)
+		if err := a.startLoopback(r, cfg, sampleRate, channels); err != nil {
+			return abort(fmt.Errorf("start recorder: %w", err))
 		}
 		rec = r
-		if withMic {
-			m, err := audio.NewMicRecorder(8)
-			if err != nil {
-				rec.Stop()
-				_ = writer.Close()
-				return "", fmt.Errorf("init mic: %w", err)
-			}
+		if m != nil {
 			if err := m.Start(sampleRate, channels); err != nil {
-				rec.Stop()
-				_ = writer.Close()
-				return "", fmt.Errorf("start mic: %w", err)
+				return abort(fmt.Errorf("start mic: %w", err))
 			}
-			mic = m
 		}
 	}
 
@@ -673,37 +1117,120 @@ func (a *App) StartRecordingAdvanced(withMic bool, dictation bool) (string, erro
 	flushTicker := time.NewTicker(500 * time.Millisecond)
 	runErrCh := make(chan error, 1)
 
+	go a.emitLevels(ctx, rec, mic)
+
+	// Long captures can roll over to a new file after a sustained silence or
+	// at a size/duration cap; multitrack is left whole so its tracks stay aligned
+	bytesPerSecond := int(sampleRate) * int(channels) * int(bits/8)
+	var splitter *silenceSplitter
+	var maxFileBytes int64
+	if micWriter == nil {
+		if cfg.AutoSplit {
+			splitter = newSilenceSplitter(cfg, bytesPerSecond)
+		}
+		maxFileBytes = rotationLimit(cfg, bytesPerSecond)
+	}
+
+	// Optional noise gate on the mic path, before it's written or mixed
+	gateMic := func([]byte) {}
+	if cfg.NoiseGate && mic != nil {
+		gate := dsp.NewNoiseGate(int(sampleRate), int(channels), cfg.NoiseGateDBFS, noiseGateAttack, noiseGateRelease)
+		gateMic = gate.ProcessS16LE
+	}
+
 	// Writer loop
+	emitAudio := a.audioDataEmitter(cfg.AudioDataFPS)
 	go func() {
 		var micBuf []byte
-		for {
-			select {
-			case <-ctx.Done():
-				runErrCh <- nil
-				return
-			default:
+		writer := writer // replaced when the recording rolls over
+		partBase, part, written := base, 1, int64(0)
+		capWarned := false
+		// rotate starts the next _partNN file if writing n more bytes would
+		// pass the size/duration cap; it runs between buffers so no frames are
+		// lost. If the new file can't be created the count is kept, so the
+		// split is retried on the next buffer, and the UI is warned once.
+		rotate := func(n int) {
+			if maxFileBytes > 0 && written > 0 && written+int64(n) > maxFileBytes {
+				next, ok, err := a.splitRecording(writer, rotationPartBase(partBase, part+1), splitRotation, cfg, sampleRate, uint16(channels), bits)
+				writer = next
+				switch {
+				case ok:
+					part++
+					written, capWarned = 0, false
+				case err != nil && !capWarned:
+					capWarned = true
+					a.emitRecordingWarning(fmt.Sprintf("file size cap not enforced, still writing to the current file: %v", err))
+				}
 			}
-
-			if dictation {
-				// Mic only path
+			written += int64(n)
+		}
+		split := func(b []byte) {
+			if splitter != nil && splitter.observe(b) {
+				next := silenceSplitBase(cfg)
+				enc, ok, err := a.splitRecording(writer, next, splitSilence, cfg, sampleRate, uint16(channels), bits)
+				writer = enc
+				if ok {
+					partBase, part, written, capWarned = next, 1, 0, false
+				} else if err != nil {
+					log.Printf("%v; continuing in the current file", err)
+				}
+			}
+		}
+		if dictation {
+			// Mic only path
+			runErrCh <- runMicLoop(ctx, mic, flushTicker.C, a.IsPaused, func(b []byte) error {
+				gateMic(b)
+				rotate(len(b))
+				if _, err := writer.Write(b); err != nil {
+					return err
+				}
+				emitAudio(b, "microphone")
+				split(b)
+				return nil
+			}, func() { _ = writer.Flush() })
+			return
+		}
+		for {
+			// Each path blocks in a single select, so cancellation is seen
+			// without a separate non-blocking check.
+			if micWriter != nil {
+				// Multitrack: each source goes to its own file unmixed
 				select {
 				case <-ctx.Done():
 					runErrCh <- nil
 					return
-				case b, ok := <-mic.Data():
+				case b, ok := <-rec.Data():
 					if !ok {
 						runErrCh <- nil
 						return
 					}
-					if len(b) > 0 {
+					if len(b) > 0 && !a.IsPaused() {
 						if _, err := writer.Write(b); err != nil {
 							runErrCh <- err
 							return
 						}
-						a.emitAudioData(b, "microphone")
+						emitAudio(b, "loopback")
+					}
+					rec.MarkWritten()
+					rec.Release(b)
+				case b, ok := <-mic.Data():
+					if !ok {
+						runErrCh <- nil
+						return
 					}
+					if len(b) > 0 && !a.IsPaused() {
+						gateMic(b)
+						if _, err := micWriter.Write(b); err != nil {
+							runErrCh <- err
+							return
+						}
+						emitAudio(b, "microphone")
+					}
+					mic.MarkWritten()
+					mic.Release(b)
 				case <-flushTicker.C:
 					_ = writer.Flush()
+					_ = micWriter.Flush()
 				}
 				continue
 			}
@@ -718,6 +1245,20 @@ func (a *App) StartRecordingAdvanced(withMic bool, dictation bool) (string, erro
 					runErrCh <- nil
 					return
 				}
+				if len(b) > 0 && a.IsPaused() {
+					// Discard while paused, draining the mic so it doesn't back up
+					if mic != nil {
+						select {
+						case micBuf = <-mic.Data():
+							mic.MarkWritten()
+							mic.Release(micBuf)
+						default:
+						}
+					}
+					rec.MarkWritten()
+					rec.Release(b)
+					continue
+				}
 				if len(b) > 0 {
 					if mic != nil {
 						select {
@@ -725,19 +1266,36 @@ func (a *App) StartRecordingAdvanced(withMic bool, dictation bool) (string, erro
 						default:
 							micBuf = nil
 						}
-						mixed := mixS16Mono(b, micBuf)
+						if micBuf != nil {
+							gateMic(micBuf)
+						}
+						mixed := mixS16(b, micBuf, int(channels))
+						rotate(len(mixed))
 						if _, err := writer.Write(mixed); err != nil {
 							runErrCh <- err
 							return
 						}
-						a.emitAudioData(mixed, "loopback")
+						rec.MarkWritten()
+						if micBuf != nil {
+							mic.MarkWritten()
+						}
+						emitAudio(mixed, "loopback")
+						split(mixed)
+						if micBuf != nil {
+							mic.Release(micBuf)
+						}
 					} else {
+						rotate(len(b))
 						if _, err := writer.Write(b); err != nil {
 							runErrCh <- err
 							return
 						}
-						a.emitAudioData(b, "loopback")
+						rec.MarkWritten()
+						emitAudio(b, "loopback")
+						split(b)
 					}
+					// Safe to recycle: the file write is synchronous and the emitter copies what it keeps
+					rec.Release(b)
 				}
 			case <-flushTicker.C:
 				_ = writer.Flush()
@@ -746,10 +1304,12 @@ func (a *App) StartRecordingAdvanced(withMic bool, dictation bool) (string, erro
 	}()
 
 	a.recording = true
+	a.paused = false
 	a.dictation = dictation
 	a.rec = rec
 	a.mic = mic
 	a.writer = writer
+	a.micWriter = micWriter
 	a.ctx = ctx
 	a.cancel = cancel
 	a.flushTicker = flushTicker
@@ -758,10 +1318,34 @@ func (a *App) StartRecordingAdvanced(withMic bool, dictation bool) (string, erro
 	return wavPath, nil
 }
 
-// SetUIContext stores the Wails runtime context for dialog APIs.
-func (a *App) SetUIContext(ctx context.Context) { a.uiCtx = ctx }
+// SetUIContext stores the Wails runtime context for dialog APIs and events.
+// It is called from OnStartup, so background work that emits events, such as
+// the transcribe-on-startup batch, is started here rather than in NewApp.
+func (a *App) SetUIContext(ctx context.Context) {
+	a.uiCtx = ctx
+	if a.settings.Get().TranscribeOnStartup {
+		a.startupOnce.Do(func() { go a.transcribeMissing() })
+	}
+}
 
-// emitAudioData sends real-time audio data to the frontend for spectrum analysis
+// Close shuts the app down: an active recording is stopped and its file
+// finalised, in-flight transcriptions and summaries are cancelled and
+// llama-server is stopped. Safe to call when idle.
+func (a *App) Close() error {
+	var err error
+	if a.IsRecording() {
+		if _, stopErr := a.StopRecording(); stopErr != nil {
+			err = fmt.Errorf("finalize recording: %w", stopErr)
+		}
+	}
+	a.transcribeOps.cancelAll()
+	a.summariseOps.cancelAll()
+	a.stopLlamaServer()
+	return err
+}
+
+// emitAudioData sends real-time audio data to the frontend for spectrum analysis.
+// The recording loop goes through audioDataEmitter, which batches and paces it.
 func (a *App) emitAudioData(data []byte, source string) {
 	if a.uiCtx != nil {
 		wruntime.EventsEmit(a.uiCtx, "audioData", map[string]interface{}{
@@ -772,16 +1356,51 @@ func (a *App) emitAudioData(data []byte, source string) {
 	}
 }
 
-// PickWavFromOutDir opens a file picker defaulting to OutDir filtered to .wav
+// levelInterval paces audioLevel events (~20 Hz).
+const levelInterval = 50 * time.Millisecond
+
+// Mic noise gate defaults; attack and release are in seconds
+const (
+	defaultNoiseGateDBFS = -50.0
+	noiseGateAttack      = 0.005
+	noiseGateRelease     = 0.15
+)
+
+// emitLevels sends an "audioLevel" event with loopback and microphone levels
+// until ctx is cancelled. Either recorder may be nil.
+func (a *App) emitLevels(ctx context.Context, rec *audio.Recorder, mic *audio.MicRecorder) {
+	if a.uiCtx == nil {
+		return
+	}
+	ticker := time.NewTicker(levelInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			levels := make(map[string]audio.Levels, 2)
+			if rec != nil {
+				levels["loopback"] = rec.Levels()
+			}
+			if mic != nil {
+				levels["microphone"] = mic.Levels()
+			}
+			wruntime.EventsEmit(a.uiCtx, "audioLevel", levels)
+		}
+	}
+}
+
+// PickWavFromOutDir opens a file picker defaulting to OutDir filtered to recordings (.wav, .ogg, .flac)
 func (a *App) PickWavFromOutDir() (string, error) {
 	if a.uiCtx == nil {
 		return "", errors.New("ui not ready")
 	}
 	cfg := a.settings.Get()
 	path, err := wruntime.OpenFileDialog(a.uiCtx, wruntime.OpenDialogOptions{
-		Title:            "Choose WAV",
+		Title:            "Choose Recording",
 		DefaultDirectory: cfg.OutDir,
-		Filters:          []wruntime.FileFilter{{DisplayName: "WAV", Pattern: "*.wav"}},
+		Filters:          []wruntime.FileFilter{{DisplayName: "Recordings", Pattern: "*.wav;*.ogg;*.flac"}},
 	})
 	if err != nil {
 		return "", err
@@ -789,7 +1408,7 @@ func (a *App) PickWavFromOutDir() (string, error) {
 	return path, nil
 }
 
-// PickTxtFromOutDir opens a file picker defaulting to OutDir filtered to .txt
+// PickTxtFromOutDir opens a file picker defaulting to the transcript directory filtered to .txt
 func (a *App) PickTxtFromOutDir() (string, error) {
 	if a.uiCtx == nil {
 		return "", errors.New("ui not ready")
@@ -797,7 +1416,7 @@ func (a *App) PickTxtFromOutDir() (string, error) {
 	cfg := a.settings.Get()
 	path, err := wruntime.OpenFileDialog(a.uiCtx, wruntime.OpenDialogOptions{
 		Title:            "Choose Transcript (.txt)",
-		DefaultDirectory: cfg.OutDir,
+		DefaultDirectory: cfg.transcriptDir(),
 		Filters:          []wruntime.FileFilter{{DisplayName: "Text", Pattern: "*.txt"}},
 	})
 	if err != nil {
@@ -835,21 +1454,12 @@ func (a *App) startLlamaServer() error {
 		a.stopLlamaServer()
 	}
 
-	cfg := a.settings.Get()
-	if cfg.LlamaModel == "" {
-		return errors.New("no model selected")
-	}
-
-	// Check if model file exists
-	if _, err := os.Stat(cfg.LlamaModel); err != nil {
-		return fmt.Errorf("model file not found: %w", err)
+	if err := a.ValidateLocalAISetup(); err != nil {
+		return err
 	}
+	cfg := a.settings.Get()
 
 	// Build llama-server command
-	llamaBin := "./llamacpp-bin/llama-server.exe"
-	if _, err := os.Stat(llamaBin); err != nil {
-		return fmt.Errorf("llama-server.exe not found in llamacpp-bin directory")
-	}
 
 	args := []string{
 		"--model", cfg.LlamaModel,
@@ -860,7 +1470,7 @@ func (a *App) startLlamaServer() error {
 		"--api-key", cfg.LlamaAPIKey,
 	}
 
-	cmd := exec.Command(llamaBin, args...)
+	cmd := exec.Command(llamaServerBin, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
@@ -954,15 +1564,27 @@ func (a *App) isLlamaServerRunning() bool {
 	return resp.StatusCode == 200
 }
 
-// GetAudioDataURL returns a base64-encoded data URL for the given WAV file
+// GetAudioDataURL returns a base64-encoded data URL for the given recording (WAV or Ogg/Opus)
 func (a *App) GetAudioDataURL(wavPath string) (string, error) {
 	// Check if file exists
-	if _, err := os.Stat(wavPath); os.IsNotExist(err) {
+	info, err := os.Stat(pathx.Long(wavPath))
+	if os.IsNotExist(err) {
 		return "", fmt.Errorf("audio file not found: %s", wavPath)
 	}
+	if err != nil {
+		return "", fmt.Errorf("failed to stat audio file: %v", err)
+	}
+
+	// Serve repeat playback from memory; a changed file misses the cache
+	cacheBytes := int64(a.settings.Get().AudioCacheMB) << 20
+	if cacheBytes > 0 {
+		if url, ok := a.audioCache.get(wavPath, info.Size(), info.ModTime()); ok {
+			return url, nil
+		}
+	}
 
 	// Read the file
-	fileData, err := os.ReadFile(wavPath)
+	fileData, err := os.ReadFile(pathx.Long(wavPath))
 	if err != nil {
 		return "", fmt.Errorf("failed to read audio file: %v", err)
 	}
@@ -970,6 +1592,32 @@ func (a *App) GetAudioDataURL(wavPath string) (string, error) {
 	// Encode as base64
 	base64Data := base64.StdEncoding.EncodeToString(fileData)
 
-	// Return as data URL
-	return "data:audio/wav;base64," + base64Data, nil
+	dataURL := "data:" + audioMIMEType(wavPath) + ";base64," + base64Data
+	if cacheBytes > 0 {
+		a.audioCache.put(wavPath, info.Size(), info.ModTime(), dataURL, cacheBytes)
+	}
+	return dataURL, nil
+}
+
+// Multitrack recordings are written as <ts>_loopback.<ext> and <ts>_mic.<ext>.
+const (
+	trackSuffixLoopback = "_loopback"
+	trackSuffixMic      = "_mic"
+)
+
+// GetTrackAudioDataURL returns a data URL for one track of a multitrack
+// recording. path may name either track; track is "loopback" or "mic".
+func (a *App) GetTrackAudioDataURL(path, track string) (string, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	base = strings.TrimSuffix(base, trackSuffixLoopback)
+	base = strings.TrimSuffix(base, trackSuffixMic)
+	switch track {
+	case "loopback":
+		return a.GetAudioDataURL(base + trackSuffixLoopback + ext)
+	case "mic", "microphone":
+		return a.GetAudioDataURL(base + trackSuffixMic + ext)
+	default:
+		return "", fmt.Errorf("unknown track %q (want loopback or mic)", track)
+	}
 }
@@ -0,0 +1,593 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"blackbox/internal/audio"
+	"blackbox/internal/wav"
+)
+
+// TestMain lets the test binary stand in for whisper-cli; see useFakeWhisper.
+func TestMain(m *testing.M) {
+	if os.Getenv("BLACKBOX_FAKE_WHISPER") != "" {
+		runFakeWhisper(os.Args[1:])
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runFakeWhisper writes a transcript to the -of base given in args, and the
+// args themselves to $BLACKBOX_FAKE_WHISPER_ARGS.
+func runFakeWhisper(args []string) {
+	if log := os.Getenv("BLACKBOX_FAKE_WHISPER_ARGS"); log != "" {
+		_ = os.WriteFile(log, []byte(strings.Join(args, "\n")), 0644)
+	}
+	for i, arg := range args {
+		if arg == "-of" && i+1 < len(args) {
+			_ = os.WriteFile(args[i+1]+".txt", []byte("fake transcript\n"), 0644)
+		}
+	}
+}
+
+// useFakeWhisper makes transcriptions run the test binary as whisper with a
+// placeholder model, and returns a func reporting the args of the last run.
+func useFakeWhisper(t *testing.T, a *App) func() []string {
+	t.Helper()
+	bin, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	model := make([]byte, 1<<20)
+	copy(model, "GGUF")
+	if err := os.WriteFile(filepath.Join(dir, "ggml-test.bin"), model, 0644); err != nil {
+		t.Fatal(err)
+	}
+	argsLog := filepath.Join(dir, "args")
+	t.Setenv("LOOPBACK_NOTES_WHISPER_BIN", bin)
+	t.Setenv("LOOPBACK_NOTES_MODELS", dir)
+	t.Setenv("BLACKBOX_FAKE_WHISPER", "1")
+	t.Setenv("BLACKBOX_FAKE_WHISPER_ARGS", argsLog)
+	cfg := a.settings.Get()
+	cfg.WhisperModel = "ggml-test.bin"
+	if err := a.settings.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	return func() []string {
+		b, err := os.ReadFile(argsLog)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Split(string(b), "\n")
+	}
+}
+
+// newTestApp returns an App backed by a settings file in a temp dir, with
+// the working directory (where ./config and ./configs live) moved there too.
+func newTestApp(t *testing.T, s UISettings) *App {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if s.OutDir == "" {
+		s.OutDir = filepath.Join(dir, "out")
+	}
+	store, err := NewSettingsStore(filepath.Join(dir, "config", "ui.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(s); err != nil {
+		t.Fatal(err)
+	}
+	return &App{
+		settings:       store,
+		selectedPrompt: "test",
+		promptCache:    map[string]PromptConfig{"test": {Name: "test", Prompt: "Summarise this."}},
+	}
+}
+
+// writeTestWAV writes interleaved 16-bit samples to a new WAV at path.
+func writeTestWAV(t *testing.T, path string, sampleRate, channels int, samples []int16) {
+	t.Helper()
+	w, err := wav.NewWriter(path, uint32(sampleRate), uint16(channels), 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, 0, len(samples)*2)
+	for _, v := range samples {
+		b = binary.LittleEndian.AppendUint16(b, uint16(v))
+	}
+	if _, err := w.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// sine returns seconds of a 440 Hz tone with the given peak amplitude
+// (0-1), repeated on every channel.
+func sine(sampleRate, channels int, seconds, amplitude float64) []int16 {
+	n := int(float64(sampleRate) * seconds)
+	out := make([]int16, 0, n*channels)
+	for i := range n {
+		v := int16(math.Round(amplitude * 32767 * math.Sin(2*math.Pi*440*float64(i)/float64(sampleRate))))
+		for range channels {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// writeRemoteConfig writes cfg to configs/remote.json in the working directory.
+func writeRemoteConfig(t *testing.T, cfg llmConfig) {
+	t.Helper()
+	b, _ := json.Marshal(cfg)
+	if err := os.MkdirAll("configs", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("configs", "remote.json"), b, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fakeRemoteLLM points configs/remote.json at a server that answers every
+// chat completion with reply, and returns the request bodies it has seen.
+func fakeRemoteLLM(t *testing.T, reply string) func() []string {
+	t.Helper()
+	var mu sync.Mutex
+	var bodies []string
+	resp, _ := json.Marshal(map[string]any{"choices": []any{map[string]any{"message": map[string]string{"content": reply}}}})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		w.Write(resp)
+	}))
+	t.Cleanup(srv.Close)
+	writeRemoteConfig(t, llmConfig{BaseURL: srv.URL, APIKey: "k", Model: "m"})
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), bodies...)
+	}
+}
+
+func TestChatCompletionsURL(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  llmConfig
+		want string
+	}{
+		{"openai", llmConfig{BaseURL: "https://api.openai.com/v1"},
+			"https://api.openai.com/v1/chat/completions"},
+		{"azure resource", llmConfig{Provider: "azure", AzureResource: "contoso", AzureDeployment: "gpt-4o", AzureAPIVersion: "2024-06-01"},
+			"https://contoso.openai.azure.com/openai/deployments/gpt-4o/chat/completions?api-version=2024-06-01"},
+		{"azure default version", llmConfig{Provider: "Azure", AzureResource: "contoso", AzureDeployment: "gpt-4o"},
+			"https://contoso.openai.azure.com/openai/deployments/gpt-4o/chat/completions?api-version=" + defaultAzureAPIVersion},
+		{"azure custom domain", llmConfig{Provider: " azure ", BaseURL: "https://llm.example.com/", AzureResource: "ignored", AzureDeployment: "notes", AzureAPIVersion: "2024-10-21"},
+			"https://llm.example.com/openai/deployments/notes/chat/completions?api-version=2024-10-21"},
+		{"azure escaping", llmConfig{Provider: "azure", AzureResource: "contoso", AzureDeployment: "my dep/1", AzureAPIVersion: "2024-10-21&x=1"},
+			"https://contoso.openai.azure.com/openai/deployments/my%20dep%2F1/chat/completions?api-version=2024-10-21%26x%3D1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.chatCompletionsURL(); got != tt.want {
+				t.Errorf("chatCompletionsURL =\n  %s\nwant\n  %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetAuthHeader(t *testing.T) {
+	tests := []struct {
+		name              string
+		provider          string
+		wantKey, wantAuth string
+	}{
+		{"openai", "", "", "Bearer sk-1"},
+		{"azure", "azure", "sk-1", ""},
+		{"azure any case", "AZURE", "sk-1", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := llmConfig{Provider: tt.provider, APIKey: "sk-1"}
+			req, err := http.NewRequest(http.MethodPost, "http://localhost", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg.setAuthHeader(req)
+			if got := req.Header.Get("api-key"); got != tt.wantKey {
+				t.Errorf("api-key = %q, want %q", got, tt.wantKey)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.wantAuth {
+				t.Errorf("Authorization = %q, want %q", got, tt.wantAuth)
+			}
+		})
+	}
+}
+
+// TestPromptCacheConcurrent exercises the prompt cache from several goroutines;
+// run with -race to catch unsynchronised map access.
+func TestPromptCacheConcurrent(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	const writers, readers, perWriter = 4, 4, 10
+
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWriter {
+				name := fmt.Sprintf("custom-%d-%d", w, i)
+				if err := a.SaveCustomPrompt(PromptConfig{Name: name, Prompt: "Prompt " + name}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	for range readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perWriter {
+				if _, err := a.GetAvailablePrompts(); err != nil {
+					t.Error(err)
+					return
+				}
+				_, _ = a.GetPromptConfig("test")
+			}
+		}()
+	}
+	wg.Wait()
+
+	prompts, err := a.GetAvailablePrompts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string, len(prompts))
+	for _, p := range prompts {
+		got[p.Key] = p.Prompt
+	}
+	for w := range writers {
+		for i := range perWriter {
+			name := fmt.Sprintf("custom-%d-%d", w, i)
+			if got[name] != "Prompt "+name {
+				t.Errorf("prompt %s = %q after concurrent saves", name, got[name])
+			}
+		}
+	}
+}
+
+// TestStartRecordingMicInitFailure checks a failed mic init rolls back the
+// files created for the recording, including the multitrack _mic track.
+func TestStartRecordingMicInitFailure(t *testing.T) {
+	errNoDevice := errors.New("no capture device")
+	origRec, origMic := newRecorder, newMicRecorder
+	t.Cleanup(func() { newRecorder, newMicRecorder = origRec, origMic })
+	newRecorder = func(int) (*audio.Recorder, error) { return nil, errNoDevice }
+	newMicRecorder = func(int) (*audio.MicRecorder, error) { return nil, errNoDevice }
+
+	tests := []struct {
+		name                           string
+		withMic, dictation, multitrack bool
+	}{
+		{"dictation", true, true, false},
+		{"loopback with mic", true, false, false},
+		{"multitrack", true, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t, UISettings{})
+			if _, err := a.startRecording(tt.withMic, tt.dictation, tt.multitrack); !errors.Is(err, errNoDevice) {
+				t.Fatalf("err = %v, want %v", err, errNoDevice)
+			}
+			if a.IsRecording() {
+				t.Error("recording flagged after a failed start")
+			}
+			entries, err := os.ReadDir(a.settings.Get().OutDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range entries {
+				t.Errorf("left behind %s", e.Name())
+			}
+		})
+	}
+}
+
+// TestSummariseAppend checks append mode adds the summary after the
+// delimiter and that summarising again replaces it rather than stacking.
+func TestSummariseAppend(t *testing.T) {
+	a := newTestApp(t, UISettings{SummaryOutput: SummaryOutputAppend})
+	fakeRemoteLLM(t, "Decided to ship on Friday.")
+	txt := filepath.Join(t.TempDir(), "meeting.txt")
+	const transcript = "We talked about the release and agreed on Friday."
+	if err := os.WriteFile(txt, []byte(transcript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := transcript + transcriptSummaryDelimiter + "Decided to ship on Friday.\n"
+	for run := 1; run <= 2; run++ {
+		if _, err := a.Summarise(txt); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(txt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("run %d: transcript = %q, want %q", run, got, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(a.settings.Get().transcriptDir(), "meeting_summary.txt")); !os.IsNotExist(err) {
+		t.Errorf("append mode wrote a separate summary file (stat err %v)", err)
+	}
+}
+
+func TestGetAvailablePromptsOrder(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll("config", 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"meeting":   "Meeting",
+		"dictation": "Dictation",
+		"gamma":     "gamma",
+		"beta":      "Beta",
+		"alpha":     "alpha",
+		"notes-b":   "Notes",
+		"notes-a":   "Notes",
+	}
+	for key, name := range files {
+		b, _ := json.Marshal(PromptConfig{Name: name, Prompt: "Prompt " + key})
+		if err := os.WriteFile(filepath.Join("config", key+".json"), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := &App{promptCache: map[string]PromptConfig{}}
+	if err := a.loadDefaultPrompts(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"dictation", "meeting", "alpha", "beta", "gamma", "notes-a", "notes-b"}
+	for run := range 5 {
+		prompts, err := a.GetAvailablePrompts()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, p := range prompts {
+			got = append(got, p.Key)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("run %d: order %v, want %v", run, got, want)
+		}
+		for i, p := range prompts {
+			if p.BuiltIn != (i < 2) {
+				t.Errorf("%s built_in = %v", p.Key, p.BuiltIn)
+			}
+		}
+	}
+}
+
+// TestTranscriptDir checks transcripts and summaries are written to
+// transcript_dir rather than next to the recordings.
+func TestTranscriptDir(t *testing.T) {
+	transcripts := filepath.Join(t.TempDir(), "transcripts")
+	a := newTestApp(t, UISettings{TranscriptDir: transcripts})
+	useFakeWhisper(t, a)
+	fakeRemoteLLM(t, "A short meeting.")
+	out := a.settings.Get().OutDir
+	if err := os.MkdirAll(out, 0755); err != nil {
+		t.Fatal(err)
+	}
+	wavPath := filepath.Join(out, "meeting.wav")
+	writeTestWAV(t, wavPath, 16000, 1, sine(16000, 1, 1, 0.5))
+
+	txt, err := a.Transcribe(wavPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(transcripts, "meeting.txt"); txt != want {
+		t.Errorf("transcript at %s, want %s", txt, want)
+	}
+	if _, err := a.Summarise(txt); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"meeting.txt", "meeting_summary.txt"} {
+		if _, err := os.Stat(filepath.Join(transcripts, name)); err != nil {
+			t.Errorf("%s not in transcript_dir: %v", name, err)
+		}
+	}
+	entries, err := os.ReadDir(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name() != "meeting.wav" {
+			t.Errorf("unexpected %s in OutDir", e.Name())
+		}
+	}
+}
+
+// stubSource is a captureSource fed by the test.
+type stubSource struct {
+	ch               chan []byte
+	marked, released atomic.Int32
+}
+
+func (s *stubSource) Data() <-chan []byte { return s.ch }
+func (s *stubSource) MarkWritten()        { s.marked.Add(1) }
+func (s *stubSource) Release([]byte)      { s.released.Add(1) }
+
+func TestRunMicLoop(t *testing.T) {
+	bufs := [][]byte{{1, 0}, {2, 0}, {}, {3, 0}}
+	errDisk := errors.New("disk full")
+	tests := []struct {
+		name      string
+		paused    bool
+		close     bool  // close the channel instead of cancelling
+		writeErr  error // returned by the first write
+		want      [][]byte
+		wantErr   error
+		processed int32 // buffers marked written and released
+	}{
+		{"cancel", false, false, nil, [][]byte{{1, 0}, {2, 0}, {3, 0}}, nil, 3},
+		{"channel closed", false, true, nil, [][]byte{{1, 0}, {2, 0}, {3, 0}}, nil, 3},
+		{"paused", true, false, nil, nil, nil, 3},
+		{"write error", false, false, errDisk, [][]byte{{1, 0}}, errDisk, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &stubSource{ch: make(chan []byte, len(bufs))}
+			for _, b := range bufs {
+				src.ch <- b
+			}
+			if tt.close {
+				close(src.ch)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			tick := make(chan time.Time)
+			flushed := make(chan struct{}, 1)
+
+			var mu sync.Mutex
+			var got [][]byte
+			write := func(b []byte) error {
+				mu.Lock()
+				defer mu.Unlock()
+				got = append(got, b)
+				return tt.writeErr
+			}
+			done := make(chan error, 1)
+			go func() {
+				done <- runMicLoop(ctx, src, tick, func() bool { return tt.paused }, write, func() { flushed <- struct{}{} })
+			}()
+
+			if tt.writeErr == nil && !tt.close {
+				// Once queued buffers are drained the loop is idle in its select
+				tick <- time.Now()
+				<-flushed
+				for src.released.Load() < tt.processed {
+					time.Sleep(time.Millisecond)
+				}
+				cancel()
+			}
+			select {
+			case err := <-done:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("loop didn't exit")
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !slices.EqualFunc(got, tt.want, bytes.Equal) {
+				t.Errorf("wrote %v, want %v", got, tt.want)
+			}
+			if m, r := src.marked.Load(), src.released.Load(); m != tt.processed || r != tt.processed {
+				t.Errorf("marked %d, released %d; want %d each", m, r, tt.processed)
+			}
+		})
+	}
+}
+
+// TestCloseFinalisesRecording checks Close stops an in-progress multitrack
+// recording and patches the RIFF sizes of both tracks.
+func TestCloseFinalisesRecording(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	dir := t.TempDir()
+	wavPath := filepath.Join(dir, "meeting.wav")
+	micPath := filepath.Join(dir, "meeting_mic.wav")
+	writer, err := wav.NewWriter(wavPath, 16000, 2, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	micWriter, err := wav.NewWriter(micPath, 16000, 1, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const loopBytes, micBytes = 16000 * 2 * 2, 8000 * 2
+	if _, err := writer.Write(make([]byte, loopBytes)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := micWriter.Write(make([]byte, micBytes)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stand in for the capture goroutine, which exits once cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	runErrCh := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		runErrCh <- ctx.Err()
+	}()
+	a.mu.Lock()
+	a.recording = true
+	a.writer = writer
+	a.micWriter = micWriter
+	a.flushTicker = time.NewTicker(time.Hour)
+	a.runErrCh = runErrCh
+	a.ctx, a.cancel = ctx, cancel
+	a.wavPath = wavPath
+	a.mu.Unlock()
+
+	start := time.Now()
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Close took %v; capture wasn't cancelled", d)
+	}
+	if a.IsRecording() {
+		t.Error("still recording after Close")
+	}
+
+	for _, tt := range []struct {
+		path string
+		data uint32
+	}{
+		{wavPath, loopBytes},
+		{micPath, micBytes},
+	} {
+		b, err := os.ReadFile(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 44+int(tt.data) {
+			t.Fatalf("%s: %d bytes, want %d", filepath.Base(tt.path), len(b), 44+tt.data)
+		}
+		if got := binary.LittleEndian.Uint32(b[4:8]); got != 36+tt.data {
+			t.Errorf("%s: RIFF size %d, want %d", filepath.Base(tt.path), got, 36+tt.data)
+		}
+		if got := binary.LittleEndian.Uint32(b[40:44]); got != tt.data {
+			t.Errorf("%s: data size %d, want %d", filepath.Base(tt.path), got, tt.data)
+		}
+	}
+
+	// Nothing to finalise the second time round
+	if err := a.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
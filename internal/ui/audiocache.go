@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// defaultAudioCacheMB caps the audio data URL cache when the setting is unset.
+const defaultAudioCacheMB = 64
+
+// audioCache is a size-bounded LRU of audio data URLs keyed by path. Entries
+// remember the file's size and modification time so a changed file misses.
+type audioCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front = most recently used
+	bytes   int64
+}
+
+type audioCacheEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+	dataURL string
+}
+
+// get returns the cached data URL for path if the file hasn't changed since.
+func (c *audioCache) get(path string, size int64, modTime time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[path]
+	if !ok {
+		return "", false
+	}
+	e := el.Value.(*audioCacheEntry)
+	if e.size != size || !e.modTime.Equal(modTime) {
+		c.removeLocked(el)
+		return "", false
+	}
+	c.order.MoveToFront(el)
+	return e.dataURL, true
+}
+
+// put caches dataURL for path, evicting least recently used entries to stay
+// within maxBytes. URLs larger than maxBytes aren't cached.
+func (c *audioCache) put(path string, size int64, modTime time.Time, dataURL string, maxBytes int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+		c.order = list.New()
+	}
+	if el, ok := c.entries[path]; ok {
+		c.removeLocked(el)
+	}
+	if int64(len(dataURL)) > maxBytes {
+		return
+	}
+	c.entries[path] = c.order.PushFront(&audioCacheEntry{path: path, size: size, modTime: modTime, dataURL: dataURL})
+	c.bytes += int64(len(dataURL))
+	for c.bytes > maxBytes {
+		c.removeLocked(c.order.Back())
+	}
+}
+
+// invalidate drops path from the cache, e.g. after the file is rewritten.
+func (c *audioCache) invalidate(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[path]; ok {
+		c.removeLocked(el)
+	}
+}
+
+func (c *audioCache) removeLocked(el *list.Element) {
+	e := c.order.Remove(el).(*audioCacheEntry)
+	delete(c.entries, e.path)
+	c.bytes -= int64(len(e.dataURL))
+}
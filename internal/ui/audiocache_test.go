@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAudioCacheEviction(t *testing.T) {
+	var c audioCache
+	mod := time.Now()
+	url := func(n int) string { return strings.Repeat("x", n) }
+
+	c.put("a", 1, mod, url(40), 100)
+	c.put("b", 1, mod, url(40), 100)
+	if _, ok := c.get("a", 1, mod); !ok { // a is now most recently used
+		t.Fatal("a missing before the cache was full")
+	}
+	c.put("c", 1, mod, url(40), 100) // 120 bytes: evicts b, the least recently used
+
+	for path, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := c.get(path, 1, mod); ok != want {
+			t.Errorf("%s cached = %v, want %v", path, ok, want)
+		}
+	}
+	if c.bytes != 80 {
+		t.Errorf("cache holds %d bytes, want 80", c.bytes)
+	}
+
+	// Exactly at the bound fits; over it replaces nothing and isn't cached
+	c.put("d", 1, mod, url(20), 100)
+	if c.bytes != 100 || len(c.entries) != 3 {
+		t.Errorf("at the bound: %d bytes in %d entries, want 100 in 3", c.bytes, len(c.entries))
+	}
+	c.put("a", 1, mod, url(101), 100)
+	if _, ok := c.get("a", 1, mod); ok || c.bytes != 60 {
+		t.Errorf("oversized entry: cached = %v, %d bytes; want dropped, 60 bytes", ok, c.bytes)
+	}
+
+	// A changed file misses and drops the stale entry
+	if _, ok := c.get("c", 2, mod); ok || c.bytes != 20 {
+		t.Errorf("changed size: cached = %v, %d bytes; want a miss, 20 bytes", ok, c.bytes)
+	}
+}
+
+// TestGetAudioDataURLCached checks a repeat request is served from the cache
+// without reading the file again, and a modified file is re-read.
+func TestGetAudioDataURLCached(t *testing.T) {
+	for _, mb := range []int{64, -1} {
+		a := newTestApp(t, UISettings{AudioCacheMB: mb}) // negative disables the cache
+		path := filepath.Join(t.TempDir(), "call.wav")
+		writeTestWAV(t, path, 16000, 1, sine(16000, 1, 0.1, 0.5))
+		first, err := a.GetAudioDataURL(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Same size and time but different bytes: only a re-read would notice
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeTestWAV(t, path, 16000, 1, sine(16000, 1, 0.1, 0.25))
+		if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+			t.Fatal(err)
+		}
+		second, err := a.GetAudioDataURL(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cached := second == first; cached != (mb > 0) {
+			t.Errorf("audio_cache_mb=%d: repeat served from cache = %v", mb, cached)
+		}
+
+		// A newer modification time misses
+		later := info.ModTime().Add(time.Second)
+		if err := os.Chtimes(path, later, later); err != nil {
+			t.Fatal(err)
+		}
+		third, err := a.GetAudioDataURL(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if third == first {
+			t.Errorf("audio_cache_mb=%d: modified file served stale data", mb)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+const (
+	// defaultAudioDataFPS paces audioData events when the setting is unset.
+	defaultAudioDataFPS = 30
+	// audioDataMaxSamples caps the samples sent per event; the spectrum
+	// analyser only needs enough to fill its bars.
+	audioDataMaxSamples = 1024
+)
+
+// audioDataBatcher coalesces captured buffers into one event per interval,
+// downsampled for visualisation. It isn't safe for concurrent use.
+type audioDataBatcher struct {
+	interval time.Duration
+	last     time.Time
+	pending  []byte
+}
+
+// add queues b and returns the batch to emit once interval has passed since
+// the previous one. The returned slice is owned by the caller.
+func (d *audioDataBatcher) add(b []byte, now time.Time) ([]byte, bool) {
+	d.pending = append(d.pending, b...)
+	if now.Sub(d.last) < d.interval {
+		return nil, false
+	}
+	out := downsamplePeaks(d.pending, audioDataMaxSamples)
+	d.pending = d.pending[:0]
+	d.last = now
+	return out, true
+}
+
+// downsamplePeaks reduces S16LE pcm to at most maxSamples samples, keeping the
+// loudest sample of each bucket so peaks still drive the bars.
+func downsamplePeaks(pcm []byte, maxSamples int) []byte {
+	n := len(pcm) / 2
+	if n <= maxSamples {
+		return append([]byte(nil), pcm[:n*2]...)
+	}
+	bucket := (n + maxSamples - 1) / maxSamples
+	out := make([]byte, 0, (n+bucket-1)/bucket*2)
+	for start := 0; start < n; start += bucket {
+		end := min(start+bucket, n)
+		var peak int16
+		for i := start; i < end; i++ {
+			s := int16(binary.LittleEndian.Uint16(pcm[i*2:]))
+			if abs16(s) > abs16(peak) {
+				peak = s
+			}
+		}
+		out = binary.LittleEndian.AppendUint16(out, uint16(peak))
+	}
+	return out
+}
+
+func abs16(s int16) int32 {
+	if s < 0 {
+		return -int32(s)
+	}
+	return int32(s)
+}
+
+// audioDataEmitter returns a function that batches buffers per source and
+// emits "audioData" at most fps times a second. fps < 0 disables the events.
+// The returned function must only be called from one goroutine.
+func (a *App) audioDataEmitter(fps int) func(b []byte, source string) {
+	if fps < 0 || a.uiCtx == nil {
+		return func([]byte, string) {}
+	}
+	if fps == 0 {
+		fps = defaultAudioDataFPS
+	}
+	interval := time.Second / time.Duration(fps)
+	batchers := make(map[string]*audioDataBatcher, 2)
+	return func(b []byte, source string) {
+		d := batchers[source]
+		if d == nil {
+			d = &audioDataBatcher{interval: interval}
+			batchers[source] = d
+		}
+		if out, ok := d.add(b, time.Now()); ok {
+			a.emitAudioData(out, source)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"blackbox/internal/dsp"
+)
+
+func TestDownsamplePeaks(t *testing.T) {
+	tests := []struct {
+		name       string
+		samples    []int16
+		maxSamples int
+		want       []int16
+	}{
+		{"under limit copies", []int16{1, -2, 3}, 4, []int16{1, -2, 3}},
+		{"at limit copies", []int16{1, -2, 3, 4}, 4, []int16{1, -2, 3, 4}},
+		{"keeps signed peak", []int16{1, -9, 3, 4, 8, -2}, 3, []int16{-9, 4, 8}},
+		{"uneven last bucket", []int16{1, 2, 3, 4, 5}, 2, []int16{3, 5}},
+		{"full scale negative", []int16{-32768, 32767, 0, 1}, 2, []int16{-32768, 1}},
+		{"empty", nil, 4, []int16{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dsp.DecodeS16LE(nil, downsamplePeaks(dsp.EncodeS16LE(nil, tt.samples), tt.maxSamples))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("downsamplePeaks = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A trailing odd byte is dropped rather than read past
+	if got := downsamplePeaks([]byte{1, 0, 2}, 4); len(got) != 2 {
+		t.Errorf("odd input gave %d bytes, want 2", len(got))
+	}
+}
+
+func TestAudioDataBatcher(t *testing.T) {
+	start := time.Unix(1000, 0)
+	buf := func(v int16, n int) []byte {
+		s := make([]int16, n)
+		for i := range s {
+			s[i] = v
+		}
+		return dsp.EncodeS16LE(nil, s)
+	}
+
+	tests := []struct {
+		name    string
+		after   time.Duration // since the previous add
+		b       []byte
+		wantOK  bool
+		wantLen int // samples in the emitted batch
+	}{
+		{"first add emits", 0, buf(1, 10), true, 10},
+		{"within interval queues", 10 * time.Millisecond, buf(2, 10), false, 0},
+		{"still within interval", 10 * time.Millisecond, buf(3, 10), false, 0},
+		{"interval passed flushes queue", 20 * time.Millisecond, buf(4, 10), true, 30},
+		{"large batch capped", 40 * time.Millisecond, buf(5, 4*audioDataMaxSamples), true, audioDataMaxSamples},
+	}
+	d := &audioDataBatcher{interval: 33 * time.Millisecond}
+	now := start
+	for _, tt := range tests {
+		now = now.Add(tt.after)
+		out, ok := d.add(tt.b, now)
+		if ok != tt.wantOK {
+			t.Fatalf("%s: ok = %v, want %v", tt.name, ok, tt.wantOK)
+		}
+		if len(out)/2 != tt.wantLen {
+			t.Errorf("%s: emitted %d samples, want %d", tt.name, len(out)/2, tt.wantLen)
+		}
+	}
+
+	// The emitted slice doesn't alias the batcher's queue
+	d = &audioDataBatcher{interval: time.Second}
+	out, _ := d.add(buf(7, 4), start)
+	d.add(buf(9, 4), start.Add(time.Millisecond))
+	if got := dsp.DecodeS16LE(nil, out); !slices.Equal(got, []int16{7, 7, 7, 7}) {
+		t.Errorf("emitted batch changed to %v", got)
+	}
+}
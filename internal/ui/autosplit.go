@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"blackbox/internal/dsp"
+	"blackbox/internal/wav"
+
+	wruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Auto-split defaults
+const (
+	defaultAutoSplitSilenceDBFS = -50.0
+	defaultAutoSplitGapSeconds  = 60
+)
+
+// silenceSplitter decides where to split a recording: after minGap of
+// continuous audio below threshold, provided there was sound since the last
+// split (so a long silence doesn't produce a string of empty files).
+type silenceSplitter struct {
+	thresholdDBFS float64
+	minGapBytes   int64
+	silentBytes   int64
+	heard         bool
+}
+
+func newSilenceSplitter(cfg UISettings, bytesPerSecond int) *silenceSplitter {
+	return &silenceSplitter{
+		thresholdDBFS: cfg.AutoSplitSilenceDBFS,
+		minGapBytes:   int64(cfg.AutoSplitGapSeconds) * int64(bytesPerSecond),
+	}
+}
+
+// observe takes the buffer just written and reports whether to start a new
+// file before the next one. Buffers hold whole frames, so splits never land
+// mid-frame.
+func (s *silenceSplitter) observe(b []byte) bool {
+	rms, _, _ := dsp.MeasureS16LE(b)
+	if dsp.DBFS(rms) >= s.thresholdDBFS {
+		s.silentBytes = 0
+		s.heard = true
+		return false
+	}
+	s.silentBytes += int64(len(b))
+	if !s.heard || s.silentBytes < s.minGapBytes {
+		return false
+	}
+	s.silentBytes = 0
+	s.heard = false
+	return true
+}
+
+// Reasons reported in RecordingSplit
+const (
+	splitSilence  = "silence"
+	splitRotation = "rotation"
+)
+
+// RecordingSplit is the payload of the "recordingSplit" event.
+type RecordingSplit struct {
+	Previous string `json:"previous"` // finished segment
+	Path     string `json:"path"`     // segment now being written
+	Reason   string `json:"reason"`   // "silence" (AutoSplit) or "rotation" (MaxFileMinutes/MaxFileMB)
+}
+
+// silenceSplitBase is the base path for a new file after a silence split.
+func silenceSplitBase(cfg UISettings) string {
+	return filepath.Join(cfg.OutDir, time.Now().Format(recordingTimeLayout))
+}
+
+// rotationPartBase is the base path for part n (2 onwards) of a recording.
+func rotationPartBase(base string, n int) string {
+	return fmt.Sprintf("%s_part%02d", base, n)
+}
+
+// rotationLimit returns the most PCM bytes to write to one file under the
+// MaxFileMinutes and MaxFileMB settings, or 0 for no limit.
+func rotationLimit(cfg UISettings, bytesPerSecond int) int64 {
+	var limit int64
+	if cfg.MaxFileMinutes > 0 {
+		limit = int64(cfg.MaxFileMinutes) * 60 * int64(bytesPerSecond)
+	}
+	if cfg.MaxFileMB > 0 {
+		if mb := int64(cfg.MaxFileMB) << 20; limit == 0 || mb < limit {
+			limit = mb
+		}
+	}
+	return limit
+}
+
+// splitRecording rolls the active recording over from current to a new file
+// at base (plus the format's extension) and finalises current. It returns the
+// encoder to keep writing to and whether it is a new one; on false it is
+// current itself, because the recording has been stopped in the meantime or
+// (with a non-nil error) the new file couldn't be created.
+func (a *App) splitRecording(current wav.Encoder, base, reason string, cfg UISettings, sampleRate uint32, channels, bits uint16) (wav.Encoder, bool, error) {
+	a.mu.Lock()
+	if !a.recording || a.writer != current {
+		a.mu.Unlock()
+		return current, false, nil
+	}
+	next, path, err := newRecordingEncoder(cfg.AudioFormat, base, sampleRate, channels, bits)
+	if err != nil {
+		a.mu.Unlock()
+		return current, false, fmt.Errorf("%s split: %w", reason, err)
+	}
+	prev := a.wavPath
+	a.writer = next
+	a.wavPath = path
+	a.mu.Unlock()
+
+	_ = current.Flush()
+	if err := current.Close(); err != nil {
+		log.Printf("%s split: finalize %s: %v", reason, prev, err)
+	}
+	if a.uiCtx != nil {
+		wruntime.EventsEmit(a.uiCtx, "recordingSplit", RecordingSplit{Previous: prev, Path: path, Reason: reason})
+	}
+	return next, true, nil
+}
+
+// RecordingWarning is the payload of the "recordingWarning" event.
+type RecordingWarning struct {
+	Path    string `json:"path"` // file being written
+	Message string `json:"message"`
+}
+
+// emitRecordingWarning logs a problem with the active recording and reports
+// it to the UI.
+func (a *App) emitRecordingWarning(message string) {
+	a.mu.Lock()
+	path := a.wavPath
+	a.mu.Unlock()
+	log.Printf("recording %s: %s", path, message)
+	if a.uiCtx != nil {
+		wruntime.EventsEmit(a.uiCtx, "recordingWarning", RecordingWarning{Path: path, Message: message})
+	}
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// charsPerToken is a rough average for English text, good enough for budgeting
+// without shipping a tokenizer.
+const charsPerToken = 4
+
+// estimateTokens approximates the token count of text.
+func estimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// trimToTokenBudget keeps the most recent part of text that fits in budget
+// tokens, starting at a word boundary. It reports whether text was trimmed.
+// A budget of 0 or less disables trimming.
+func trimToTokenBudget(text string, budget int) (string, bool) {
+	if budget <= 0 || estimateTokens(text) <= budget {
+		return text, false
+	}
+	start := len(text) - budget*charsPerToken
+	// Don't start mid-rune or mid-word
+	for start < len(text) && !utf8.RuneStart(text[start]) {
+		start++
+	}
+	if i := strings.IndexFunc(text[start:], unicode.IsSpace); i >= 0 {
+		start += i
+	}
+	return strings.TrimLeftFunc(text[start:], unicode.IsSpace), true
+}
+
+// truncationNote is prepended to summaries of trimmed transcripts so the
+// saved summary records that it only covers part of the recording.
+func truncationNote(budget int) string {
+	return fmt.Sprintf("> Note: the transcript exceeded the %d-token budget; only its most recent part was summarised.\n\n", budget)
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTrimToTokenBudget(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		budget      int
+		want        string
+		wantTrimmed bool
+	}{
+		{"disabled", "one two three four five six", 0, "one two three four five six", false},
+		{"negative disables", "one two three four five six", -1, "one two three four five six", false},
+		{"fits", "one two three", 4, "one two three", false},
+		{"exact fit", "12345678", 2, "12345678", false},
+		{"keeps the end at a word boundary", "one two three four five six", 3, "five six", true},
+		{"boundary already at a space", "aaaa bbbb cccc", 2, "cccc", true},
+		{"newlines count as spaces", "first line\nsecond line\nlast", 2, "last", true},
+		{"single long word", "abcdefghijklmnop", 2, "ijklmnop", true},
+		{"no mid-rune start", "ééééééé", 2, "éééé", true},
+		{"multibyte words", "naïve café résumé über", 3, "über", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, trimmed := trimToTokenBudget(tt.text, tt.budget)
+			if got != tt.want || trimmed != tt.wantTrimmed {
+				t.Errorf("trimToTokenBudget = (%q, %v), want (%q, %v)", got, trimmed, tt.want, tt.wantTrimmed)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("result %q is not valid UTF-8", got)
+			}
+			if tt.budget > 0 && estimateTokens(got) > tt.budget {
+				t.Errorf("result is %d tokens, over the %d budget", estimateTokens(got), tt.budget)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errCanceledByUser is returned when an operation is stopped via CancelTranscribe
+// or CancelSummarise.
+var errCanceledByUser = errors.New("canceled by user")
+
+// cancelSet tracks the cancel funcs of in-flight operations of one kind.
+type cancelSet struct {
+	mu     sync.Mutex
+	next   int
+	active map[int]context.CancelFunc
+}
+
+// begin registers a new operation. done must be called when it finishes.
+func (s *cancelSet) begin() (ctx context.Context, done func()) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	s.mu.Lock()
+	if s.active == nil {
+		s.active = make(map[int]context.CancelFunc)
+	}
+	id := s.next
+	s.next++
+	s.active[id] = func() { cancel(errCanceledByUser) }
+	s.mu.Unlock()
+	return ctx, func() {
+		s.mu.Lock()
+		delete(s.active, id)
+		s.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+// cancelAll cancels every in-flight operation, reporting whether there were any.
+func (s *cancelSet) cancelAll() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, cancel := range s.active {
+		cancel()
+	}
+	return len(s.active) > 0
+}
+
+// canceledErr returns errCanceledByUser if ctx was stopped by the user, else err.
+func canceledErr(ctx context.Context, err error) error {
+	if errors.Is(context.Cause(ctx), errCanceledByUser) {
+		return errCanceledByUser
+	}
+	return err
+}
+
+// CancelTranscribe stops any in-flight transcription. Partial transcript and
+// log files are removed. Returns false if nothing was running.
+func (a *App) CancelTranscribe() bool {
+	return a.transcribeOps.cancelAll()
+}
+
+// CancelSummarise stops any in-flight summarisation; no summary is written.
+// Returns false if nothing was running.
+func (a *App) CancelSummarise() bool {
+	return a.summariseOps.cancelAll()
+}
@@ -0,0 +1,374 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	wruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"blackbox/internal/execx"
+	"blackbox/internal/pathx"
+	"blackbox/internal/wav"
+)
+
+// Chunked transcription defaults
+const (
+	defaultChunkSeconds = 600 // 10 minute chunks
+	chunkOverlapSeconds = 2   // overlap so words at boundaries aren't lost
+	maxOverlapWords     = 40  // longest run of words considered when deduplicating overlap
+	minOverlapWords     = 3   // shorter matches are likely coincidental ("the", "and so")
+)
+
+// TranscriptionProgress is emitted as a "transcribeProgress" event after each
+// chunk of TranscribeChunked.
+type TranscriptionProgress struct {
+	Chunk  int    `json:"chunk"` // 1-based index of the finished chunk
+	Total  int    `json:"total"`
+	Text   string `json:"text"` // text added by this chunk after overlap removal
+	Source string `json:"source"`
+	// ETASeconds estimates the time left for the remaining chunks (0 if unknown)
+	ETASeconds float64 `json:"eta_seconds"`
+}
+
+// WhisperProgress is emitted as a "whisperProgress" event while whisper runs
+// on a whole file in Transcribe.
+type WhisperProgress struct {
+	Source  string  `json:"source"`
+	Percent float64 `json:"percent"`           // 0-100
+	Segment string  `json:"segment,omitempty"` // latest "[start --> end] text" line
+}
+
+// TranscribeChunked transcribes a long recording in fixed-length chunks, emitting
+// progress per chunk and writing the merged transcript to TranscriptDir.
+// chunkSeconds <= 0 uses the default (10 minutes). Returns the transcript path.
+func (a *App) TranscribeChunked(wavPath string, chunkSeconds int) (string, error) {
+	if strings.TrimSpace(wavPath) == "" {
+		return "", errors.New("wav path required")
+	}
+	if chunkSeconds <= 0 {
+		chunkSeconds = defaultChunkSeconds
+	}
+	cfg := a.settings.Get()
+	outDir := cfg.transcriptDir()
+	if err := os.MkdirAll(pathx.Long(outDir), 0755); err != nil {
+		return "", err
+	}
+	source := wavPath
+	ctx, done := a.transcribeOps.begin()
+	defer done()
+
+	// Compressed recordings are decoded to a temporary WAV first
+	if !isWAVFile(wavPath) {
+		decoded, cleanup, err := decodeForTranscription(wavPath)
+		if err != nil {
+			return "", fmt.Errorf("decode for transcription: %w", err)
+		}
+		defer cleanup()
+		wavPath = decoded
+	}
+
+	// Higher-rate or stereo recordings get a temporary 16 kHz mono copy
+	if !cfg.SkipWhisperConversion {
+		converted, cleanup, err := convertForWhisper(wavPath)
+		if err != nil {
+			return "", fmt.Errorf("prepare for transcription: %w", err)
+		}
+		defer cleanup()
+		wavPath = converted
+	}
+
+	r, err := wav.OpenReader(wavPath)
+	if err != nil {
+		return "", fmt.Errorf("open wav: %w", err)
+	}
+	defer r.Close()
+	if r.BitsPerSample() != 16 {
+		return "", fmt.Errorf("chunked transcription needs 16-bit PCM, got %d-bit", r.BitsPerSample())
+	}
+
+	tmpDir, err := os.MkdirTemp("", "blackbox-chunks-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	whisperBin := getenvDefault("LOOPBACK_NOTES_WHISPER_BIN", "./whisper-bin/whisper-cli.exe")
+	modelPath := whisperModelPath(cfg.WhisperModel)
+	lang := recordingLanguage(source, cfg)
+
+	chunks := splitRanges(r.DataSize(), chunkBytes(r, chunkSeconds), chunkBytes(r, chunkOverlapSeconds))
+	workers := chunkWorkers(cfg.ChunkParallelism)
+	threads := whisperThreads(workers)
+	transcribe := func(ctx context.Context, i int) (string, error) {
+		c := chunks[i]
+		chunkPath := filepath.Join(tmpDir, fmt.Sprintf("chunk_%03d.wav", i+1))
+		if err := writeChunk(r, chunkPath, c[0], c[1]); err != nil {
+			return "", fmt.Errorf("write chunk %d: %w", i+1, err)
+		}
+		defer os.Remove(chunkPath)
+		started := time.Now()
+		txtPath, err := execx.RunWhisperWithProgress(ctx, whisperBin, modelPath, chunkPath, tmpDir, lang, threads, "", nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", canceledErr(ctx, err)
+			}
+			return "", fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+		a.transcribeSpeed.observe(bytesDuration(r, c[1]), time.Since(started))
+		b, err := os.ReadFile(txtPath)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+
+	var merged string
+	err = runChunks(ctx, len(chunks), workers, transcribe, func(i int, text string) {
+		before := len(merged)
+		merged = mergeOverlap(merged, text)
+		var remaining int64
+		for _, next := range chunks[i+1:] {
+			remaining += next[1]
+		}
+		a.emitTranscribeProgress(TranscriptionProgress{
+			Chunk:      i + 1,
+			Total:      len(chunks),
+			Text:       strings.TrimSpace(merged[before:]),
+			Source:     source,
+			ETASeconds: a.EstimateTranscriptionTime(bytesDuration(r, remaining).Seconds()).Seconds(),
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	outPath := filepath.Join(outDir, base+".txt")
+	if err := os.WriteFile(pathx.Long(outPath), []byte(merged+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("write transcript: %w", err)
+	}
+	return outPath, nil
+}
+
+// chunkWorkers clamps the configured parallelism to [1, NumCPU].
+func chunkWorkers(n int) int {
+	if n < 1 {
+		return 1
+	}
+	if cpus := runtime.NumCPU(); n > cpus {
+		return cpus
+	}
+	return n
+}
+
+// whisperThreads splits the CPUs between workers concurrent whisper runs so
+// they don't oversubscribe the machine. A single worker uses whisper's
+// default (0).
+func whisperThreads(workers int) int {
+	if workers <= 1 {
+		return 0
+	}
+	return max(runtime.NumCPU()/workers, 1)
+}
+
+// runChunks calls transcribe for chunks 0..n-1 with at most workers running at
+// once, and calls merge for each result strictly in chunk order so overlap
+// dedup sees the same sequence as a serial run. Stops at the first error,
+// cancelling the ctx passed to chunks still in flight, and returns that error.
+func runChunks(ctx context.Context, n, workers int, transcribe func(ctx context.Context, i int) (string, error), merge func(i int, text string)) error {
+	type result struct {
+		text string
+		err  error
+	}
+	results := make([]chan result, n)
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	var (
+		failOnce sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		failOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	// On error, wait for in-flight chunks so none outlive the temp directory
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				text, err := transcribe(ctx, i)
+				if err != nil {
+					fail(err)
+				}
+				results[i] <- result{text, err}
+			}(i)
+		}
+	}()
+
+	for i := 0; i < n; i++ {
+		select {
+		case res := <-results[i]:
+			if res.err != nil {
+				return firstErr
+			}
+			merge(i, res.text)
+		case <-ctx.Done():
+			// A later chunk failed, or the caller cancelled before this one started
+			fail(ctx.Err())
+			return firstErr
+		}
+	}
+	return nil
+}
+
+func (a *App) emitTranscribeProgress(p TranscriptionProgress) {
+	if a.uiCtx != nil {
+		wruntime.EventsEmit(a.uiCtx, "transcribeProgress", p)
+	}
+}
+
+// chunkBytes returns the frame-aligned byte length of seconds of audio in r.
+func chunkBytes(r *wav.Reader, seconds int) int64 {
+	frame := int64(r.Channels()) * int64(r.BitsPerSample()) / 8
+	return int64(r.SampleRate()) * int64(seconds) * frame
+}
+
+// bytesDuration returns the playback length of n bytes of r's sample data.
+func bytesDuration(r *wav.Reader, n int64) time.Duration {
+	perSec := chunkBytes(r, 1)
+	if perSec == 0 {
+		return 0
+	}
+	return time.Duration(n * int64(time.Second) / perSec)
+}
+
+// splitRanges divides size bytes into [offset, length] ranges of chunk bytes,
+// each starting overlap bytes before the previous one ends.
+func splitRanges(size, chunk, overlap int64) [][2]int64 {
+	if chunk <= 0 || size <= chunk {
+		return [][2]int64{{0, size}}
+	}
+	if overlap >= chunk {
+		overlap = 0
+	}
+	var ranges [][2]int64
+	for off := int64(0); off < size; off += chunk - overlap {
+		n := chunk
+		if off+n > size {
+			n = size - off
+		}
+		ranges = append(ranges, [2]int64{off, n})
+		if off+n >= size {
+			break
+		}
+	}
+	return ranges
+}
+
+// writeChunk copies n bytes of sample data starting at off into a new WAV at path.
+func writeChunk(r *wav.Reader, path string, off, n int64) error {
+	w, err := wav.NewWriter(path, r.SampleRate(), r.Channels(), r.BitsPerSample())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, io.NewSectionReader(r, off, n)); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// mergeOverlap appends next to prev, dropping the longest run of leading words
+// in next that repeats the trailing words of prev (the overlap between chunks).
+// Words are compared case-insensitively, ignoring punctuation. Runs shorter
+// than minOverlapWords are kept, so a chance repeat doesn't drop a real word.
+func mergeOverlap(prev, next string) string {
+	if prev == "" {
+		return next
+	}
+	if next == "" {
+		return prev
+	}
+	prevWords := strings.Fields(prev)
+	nextWords := strings.Fields(next)
+
+	limit := maxOverlapWords
+	if len(prevWords) < limit {
+		limit = len(prevWords)
+	}
+	if len(nextWords) < limit {
+		limit = len(nextWords)
+	}
+	overlap := 0
+	for k := limit; k >= minOverlapWords; k-- {
+		if wordsEqual(prevWords[len(prevWords)-k:], nextWords[:k]) {
+			overlap = k
+			break
+		}
+	}
+
+	rest := skipWords(next, overlap)
+	if rest == "" {
+		return prev
+	}
+	return prev + " " + rest
+}
+
+// skipWords returns s with its first n whitespace-separated words removed,
+// preserving the formatting of the remainder.
+func skipWords(s string, n int) string {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	for ; n > 0 && s != ""; n-- {
+		end := strings.IndexFunc(s, unicode.IsSpace)
+		if end < 0 {
+			return ""
+		}
+		s = strings.TrimLeftFunc(s[end:], unicode.IsSpace)
+	}
+	return s
+}
+
+func wordsEqual(a, b []string) bool {
+	for i := range a {
+		if normaliseWord(a[i]) != normaliseWord(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// normaliseWord lowercases w and strips punctuation so "Hello," matches "hello".
+func normaliseWord(w string) string {
+	return strings.ToLower(strings.TrimFunc(w, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r)
+	}))
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeChunks returns n chunk texts where each repeats the last three words
+// of the previous one, like whisper output for overlapping audio.
+func fakeChunks(n int) []string {
+	texts := make([]string, n)
+	for i := range texts {
+		var words []string
+		if i > 0 {
+			prev := strings.Fields(texts[i-1])
+			words = append(words, prev[len(prev)-3:]...)
+		}
+		for j := range 20 {
+			words = append(words, fmt.Sprintf("c%dw%d", i, j))
+		}
+		texts[i] = strings.Join(words, " ")
+	}
+	return texts
+}
+
+func TestRunChunksParallelMatchesSerial(t *testing.T) {
+	texts := fakeChunks(12)
+	run := func(workers int) string {
+		var merged string
+		err := runChunks(context.Background(), len(texts), workers, func(ctx context.Context, i int) (string, error) {
+			// Finish out of order
+			time.Sleep(time.Duration(rand.IntN(3)) * time.Millisecond)
+			return texts[i], nil
+		}, func(i int, text string) {
+			merged = mergeOverlap(merged, text)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return merged
+	}
+	serial := run(1)
+	if got := len(strings.Fields(serial)); got != 12*20 {
+		t.Fatalf("serial run has %d words, want %d", got, 12*20)
+	}
+	for _, workers := range []int{2, 4, 12} {
+		if got := run(workers); got != serial {
+			t.Errorf("%d workers:\n%s\nwant\n%s", workers, got, serial)
+		}
+	}
+}
+
+func TestRunChunksCancelsInFlightOnError(t *testing.T) {
+	boom := errors.New("chunk 3 failed")
+	var merged, cancelled atomic.Int32
+	done := make(chan error, 1)
+	go func() {
+		done <- runChunks(context.Background(), 8, 4, func(ctx context.Context, i int) (string, error) {
+			if i == 2 {
+				return "", boom
+			}
+			// The other chunks run until cancelled
+			select {
+			case <-ctx.Done():
+				cancelled.Add(1)
+				return "", ctx.Err()
+			case <-time.After(10 * time.Second):
+				return "late", nil
+			}
+		}, func(int, string) { merged.Add(1) })
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, boom) {
+			t.Errorf("runChunks returned %v, want %v", err, boom)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runChunks waited for in-flight chunks instead of cancelling them")
+	}
+	if merged.Load() != 0 {
+		t.Errorf("merged %d chunks before the failure", merged.Load())
+	}
+	if cancelled.Load() == 0 {
+		t.Error("no in-flight chunk saw cancellation")
+	}
+}
+
+func TestRunChunksHonoursCallerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := runChunks(ctx, 4, 2, func(ctx context.Context, i int) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}, func(int, string) {})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("runChunks returned %v, want context.Canceled", err)
+	}
+}
+
+func TestMergeOverlap(t *testing.T) {
+	tests := []struct {
+		name, prev, next, want string
+	}{
+		{"empty prev", "", "hello there", "hello there"},
+		{"empty next", "hello there", "", "hello there"},
+		{"no overlap", "we met on Monday", "then we left", "we met on Monday then we left"},
+		{"overlap", "so the plan is to ship on Friday", "ship on Friday and then review", "so the plan is to ship on Friday and then review"},
+		{"case and punctuation", "the budget is approved.", "Budget is approved, next item", "the budget is approved. next item"},
+		{"whole next repeated", "and that is the end of it", "the end of it", "and that is the end of it"},
+		{"one-word repeat kept", "we looked at the", "the numbers again", "we looked at the the numbers again"},
+		{"two-word repeat kept", "check in with and so", "and so on", "check in with and so and so on"},
+		{"longest overlap wins", "a b c a b c", "a b c a b c d", "a b c a b c d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeOverlap(tt.prev, tt.next); got != tt.want {
+				t.Errorf("mergeOverlap(%q, %q) = %q, want %q", tt.prev, tt.next, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"blackbox/internal/pathx"
+)
+
+// recordingTimeLayout is the timestamp StartRecordingAdvanced uses for file names.
+const recordingTimeLayout = "20060102_150405"
+
+type dailyTranscript struct {
+	path  string
+	title string
+	at    time.Time
+}
+
+// ExportDailyTranscripts writes every transcript recorded on day (local time)
+// into one file, oldest first, each under a time and title heading. An empty
+// destPath writes daily_<YYYYMMDD>.txt in the transcript directory. Returns
+// the path written.
+func (a *App) ExportDailyTranscripts(day time.Time, destPath string) (string, error) {
+	cfg := a.settings.Get()
+	dir := cfg.transcriptDir()
+	day = day.Local()
+	if strings.TrimSpace(destPath) == "" {
+		destPath = filepath.Join(dir, "daily_"+day.Format("20060102")+".txt")
+	}
+
+	transcripts, err := transcriptsForDay(dir, day)
+	if err != nil {
+		return "", err
+	}
+	if len(transcripts) == 0 {
+		return "", fmt.Errorf("no transcripts found for %s", day.Format("2006-01-02"))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Transcripts for %s\n", day.Format("Monday, 2 January 2006"))
+	for _, t := range transcripts {
+		b, err := os.ReadFile(pathx.Long(t.path))
+		if err != nil {
+			return "", fmt.Errorf("read %s: %w", t.path, err)
+		}
+		fmt.Fprintf(&sb, "\n===== %s - %s =====\n\n", t.at.Format("15:04"), t.title)
+		sb.WriteString(strings.TrimSpace(stripAppendedSummary(string(b))))
+		sb.WriteString("\n")
+	}
+
+	if err := os.MkdirAll(pathx.Long(filepath.Dir(destPath)), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(pathx.Long(destPath), []byte(sb.String()), 0644); err != nil {
+		return "", fmt.Errorf("write daily transcript: %w", err)
+	}
+	return destPath, nil
+}
+
+// transcriptsForDay lists transcripts in dir recorded on day. The recording time
+// comes from the timestamped file name, or the modification time otherwise.
+// Summaries and earlier daily exports are skipped.
+func transcriptsForDay(dir string, day time.Time) ([]dailyTranscript, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	y, m, d := day.Date()
+	var out []dailyTranscript
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(name), ".txt") {
+			continue
+		}
+		base := strings.TrimSuffix(name, filepath.Ext(name))
+		if strings.HasSuffix(base, "_summary") || strings.HasPrefix(base, "daily_") {
+			continue
+		}
+		at, err := time.ParseInLocation(recordingTimeLayout, base, time.Local)
+		if err != nil {
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			at = info.ModTime().Local()
+		}
+		if ay, am, ad := at.Date(); ay != y || am != m || ad != d {
+			continue
+		}
+		out = append(out, dailyTranscript{path: filepath.Join(dir, name), title: base, at: at})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].at.Before(out[j].at) })
+	return out, nil
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExportDailyTranscripts(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	dir := a.settings.Get().transcriptDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"20261016_143000.txt":         "Afternoon standup." + transcriptSummaryDelimiter + "Summary of the standup.\n",
+		"20261016_090000.txt":         "  Morning planning.\n\n",
+		"20261016_090000_summary.txt": "Summary of planning.",
+		"daily_20261016.txt":          "Stale export.",
+		"20261015_235959.txt":         "Yesterday's late call.",
+		"notes.txt":                   "Notes saved at noon.",
+		"20261016_100000.wav":         "not a transcript",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Untimestamped transcripts are placed by their modification time
+	noon := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	if err := os.Chtimes(filepath.Join(dir, "notes.txt"), noon, noon); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := a.ExportDailyTranscripts(noon, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "daily_20261016.txt"); path != want {
+		t.Errorf("written to %s, want %s", path, want)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Transcripts for Friday, 16 October 2026\n" +
+		"\n===== 09:00 - 20261016_090000 =====\n\nMorning planning.\n" +
+		"\n===== 12:00 - notes =====\n\nNotes saved at noon.\n" +
+		"\n===== 14:30 - 20261016_143000 =====\n\nAfternoon standup.\n"
+	if string(got) != want {
+		t.Errorf("daily export =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := a.ExportDailyTranscripts(noon.AddDate(0, 0, 2), ""); err == nil {
+		t.Error("export of a day with no transcripts succeeded")
+	}
+}
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"blackbox/internal/dsp"
+	"blackbox/internal/execx"
+	"blackbox/internal/wav"
+)
+
+// Recording output formats
+const (
+	AudioFormatWAV  = "wav"
+	AudioFormatOpus = "opus"
+	AudioFormatFLAC = "flac"
+)
+
+func ffmpegBin() string {
+	return getenvDefault("LOOPBACK_NOTES_FFMPEG_BIN", "./ffmpeg-bin/ffmpeg.exe")
+}
+
+// newRecordingEncoder creates the output file for a recording at basePath (no extension)
+// in the configured format and returns the encoder and the full file path.
+func newRecordingEncoder(format, basePath string, sampleRate uint32, channels, bits uint16) (wav.Encoder, string, error) {
+	switch format {
+	case AudioFormatOpus:
+		path := basePath + ".ogg"
+		enc, err := execx.NewFFmpegEncoder(ffmpegBin(), path, int(sampleRate), int(channels), execx.OpusCodecArgs)
+		if err != nil {
+			return nil, "", fmt.Errorf("open opus encoder: %w", err)
+		}
+		return enc, path, nil
+	case AudioFormatFLAC:
+		path := basePath + ".flac"
+		enc, err := execx.NewFFmpegEncoder(ffmpegBin(), path, int(sampleRate), int(channels), execx.FLACCodecArgs)
+		if err != nil {
+			return nil, "", fmt.Errorf("open flac encoder: %w", err)
+		}
+		return enc, path, nil
+	default:
+		path := basePath + ".wav"
+		w, err := wav.NewWriter(path, sampleRate, channels, bits)
+		if err != nil {
+			return nil, "", fmt.Errorf("open wav: %w", err)
+		}
+		return w, path, nil
+	}
+}
+
+// isWAVFile reports whether path holds RIFF/WAVE data, judged by its content
+// so mislabelled files go through ffmpeg. Falls back to the extension if the
+// file can't be read.
+func isWAVFile(path string) bool {
+	_, ok, err := wav.SniffFile(path)
+	if err != nil {
+		return strings.EqualFold(filepath.Ext(path), ".wav")
+	}
+	return ok
+}
+
+// decodeForTranscription converts a compressed recording into a temporary WAV for whisper.
+// The WAV keeps the recording's base name so whisper's outputs are named after it.
+// The returned cleanup func removes the temporary file.
+func decodeForTranscription(audioPath string) (string, func(), error) {
+	tmpDir, err := os.MkdirTemp("", "blackbox-decode-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmpDir) }
+	base := strings.TrimSuffix(filepath.Base(audioPath), filepath.Ext(audioPath))
+	wavPath := filepath.Join(tmpDir, base+".wav")
+	if err := execx.DecodeToWAV(ffmpegBin(), audioPath, wavPath); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return wavPath, cleanup, nil
+}
+
+// Whisper works on 16 kHz mono audio
+const whisperSampleRate = 16000
+
+// needsWhisperConversion reports whether the 16-bit PCM WAV in r differs from
+// whisper's native format.
+func needsWhisperConversion(r *wav.Reader) bool {
+	return r.AudioFormat() == 1 && r.BitsPerSample() == 16 &&
+		(r.SampleRate() != whisperSampleRate || r.Channels() != 1)
+}
+
+// convertForWhisper writes a temporary 16 kHz mono copy of a WAV recorded at
+// another rate or channel count, leaving the original untouched. Files already
+// in that format (or not 16-bit PCM) are returned as-is with a no-op cleanup.
+// The copy keeps the recording's base name so whisper's outputs are named after it.
+func convertForWhisper(wavPath string) (string, func(), error) {
+	r, err := wav.OpenReader(wavPath)
+	if err != nil {
+		return "", nil, err
+	}
+	defer r.Close()
+	if !needsWhisperConversion(r) {
+		return wavPath, func() {}, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "blackbox-convert-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmpDir) }
+	base := strings.TrimSuffix(filepath.Base(wavPath), filepath.Ext(wavPath))
+	outPath := filepath.Join(tmpDir, base+".wav")
+	if err := writeWhisperWAV(r, outPath); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("convert to 16 kHz mono: %w", err)
+	}
+	return outPath, cleanup, nil
+}
+
+// writeWhisperWAV downmixes and resamples r's samples block by block into a
+// 16 kHz mono WAV at path.
+func writeWhisperWAV(r *wav.Reader, path string) error {
+	w, err := wav.NewWriter(path, whisperSampleRate, 1, 16)
+	if err != nil {
+		return err
+	}
+	channels := int(r.Channels())
+	resampler := dsp.NewResampler(int(r.SampleRate()), whisperSampleRate)
+	buf := make([]byte, 4096*2*channels) // whole frames per read
+	var samples, mono, out []int16
+	var outBytes []byte
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			samples = dsp.DecodeS16LE(samples, buf[:n-n%(2*channels)])
+			mono = dsp.Downmix(mono, samples, channels)
+			out = resampler.Process(out[:0], mono)
+			outBytes = dsp.EncodeS16LE(outBytes, out)
+			if _, err := w.Write(outBytes); err != nil {
+				w.Close()
+				return err
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			w.Close()
+			return readErr
+		}
+	}
+	return w.Close()
+}
+
+// audioMIMEType returns the data URL MIME type for a recording file.
+func audioMIMEType(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ogg", ".opus":
+		return "audio/ogg"
+	case ".flac":
+		return "audio/flac"
+	default:
+		return "audio/wav"
+	}
+}
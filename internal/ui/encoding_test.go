@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"blackbox/internal/dsp"
+	"blackbox/internal/wav"
+)
+
+// requireFFmpeg points LOOPBACK_NOTES_FFMPEG_BIN at an ffmpeg on PATH, or
+// skips the test if there isn't one.
+func requireFFmpeg(t *testing.T) {
+	t.Helper()
+	if _, err := os.Stat(ffmpegBin()); err == nil {
+		return
+	}
+	path, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		t.Skip("ffmpeg not available")
+	}
+	t.Setenv("LOOPBACK_NOTES_FFMPEG_BIN", path)
+}
+
+// TestOpusRoundTrip records a second of audio to Ogg/Opus, checks it's listed
+// with its probed format, and decodes it back to a whisper-ready WAV.
+func TestOpusRoundTrip(t *testing.T) {
+	a := newTestApp(t, UISettings{AudioFormat: AudioFormatOpus})
+	requireFFmpeg(t)
+	out := a.settings.Get().OutDir
+	if err := os.MkdirAll(out, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	enc, path, err := newRecordingEncoder(AudioFormatOpus, filepath.Join(out, "call"), 16000, 1, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := enc.Write(dsp.EncodeS16LE(nil, sine(16000, 1, 1, 0.5))); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Ext(path) != ".ogg" {
+		t.Fatalf("opus recording written to %s", path)
+	}
+
+	recs, err := a.listRecordings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].Name != "call.ogg" {
+		t.Fatalf("listed %+v, want call.ogg", recs)
+	}
+	if rec := recs[0]; rec.SampleRate == 0 || rec.Channels != 1 || math.Abs(rec.DurationSeconds-1) > 0.1 {
+		t.Errorf("probed %d Hz, %d channels, %.2fs; want mono, about 1s", rec.SampleRate, rec.Channels, rec.DurationSeconds)
+	}
+
+	wavPath, cleanup, err := decodeForTranscription(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	r, err := wav.OpenReader(wavPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if r.SampleRate() != 16000 || r.Channels() != 1 || r.BitsPerSample() != 16 {
+		t.Errorf("decoded %d Hz, %d channels, %d-bit; want 16 kHz mono 16-bit", r.SampleRate(), r.Channels(), r.BitsPerSample())
+	}
+	if d := r.Duration().Seconds(); math.Abs(d-1) > 0.1 {
+		t.Errorf("decoded duration %.2fs, want about 1s", d)
+	}
+}
+
+func TestConvertForWhisper(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate, ch int
+		convert  bool
+	}{
+		{"48 kHz stereo", 48000, 2, true},
+		{"44.1 kHz mono", 44100, 1, true},
+		{"16 kHz stereo", 16000, 2, true},
+		{"already 16 kHz mono", 16000, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := filepath.Join(t.TempDir(), "call.wav")
+			writeTestWAV(t, src, tt.rate, tt.ch, sine(tt.rate, tt.ch, 2, 0.5))
+			before, err := os.ReadFile(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out, cleanup, err := convertForWhisper(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.convert {
+				cleanup()
+				if out != src {
+					t.Errorf("16 kHz mono converted to %s", out)
+				}
+				return
+			}
+			if out == src || filepath.Base(out) != "call.wav" {
+				t.Errorf("converted to %s, want a temporary call.wav", out)
+			}
+			r, err := wav.OpenReader(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.SampleRate() != whisperSampleRate || r.Channels() != 1 || r.BitsPerSample() != 16 || r.AudioFormat() != 1 {
+				t.Errorf("converted to %d Hz, %d channels, %d-bit format %d", r.SampleRate(), r.Channels(), r.BitsPerSample(), r.AudioFormat())
+			}
+			if d := r.Duration().Seconds(); math.Abs(d-2) > 0.01 {
+				t.Errorf("converted duration %.3fs, want 2s", d)
+			}
+			r.Close()
+
+			after, err := os.ReadFile(src)
+			if err != nil || !bytes.Equal(before, after) {
+				t.Errorf("source changed by conversion (read err %v)", err)
+			}
+			cleanup()
+			if _, err := os.Stat(out); !os.IsNotExist(err) {
+				t.Errorf("cleanup left %s (stat err %v)", out, err)
+			}
+		})
+	}
+}
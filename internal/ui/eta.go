@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"sync"
+	"time"
+
+	"blackbox/internal/wav"
+)
+
+// speedSmoothing weights the newest observation in the moving average.
+const speedSmoothing = 0.3
+
+// speedTracker keeps an exponential moving average of transcription speed in
+// audio seconds per wall-clock second.
+type speedTracker struct {
+	mu    sync.Mutex
+	speed float64
+}
+
+// observe records that audio of length audioDur took wall to transcribe.
+func (t *speedTracker) observe(audioDur, wall time.Duration) {
+	if audioDur <= 0 || wall <= 0 {
+		return
+	}
+	speed := audioDur.Seconds() / wall.Seconds()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.speed == 0 {
+		t.speed = speed
+		return
+	}
+	t.speed = speedSmoothing*speed + (1-speedSmoothing)*t.speed
+}
+
+// estimate returns the expected wall time for audioSeconds, or 0 if no
+// transcription has been timed yet.
+func (t *speedTracker) estimate(audioSeconds float64) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.speed == 0 || audioSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(audioSeconds / t.speed * float64(time.Second))
+}
+
+// EstimateTranscriptionTime predicts how long transcribing audioSeconds of
+// audio will take, based on transcriptions timed this session. Returns 0 when
+// there is no history yet.
+func (a *App) EstimateTranscriptionTime(audioSeconds float64) time.Duration {
+	return a.transcribeSpeed.estimate(audioSeconds)
+}
+
+// wavDuration returns the playback length of a WAV, or 0 if it can't be read.
+func wavDuration(path string) time.Duration {
+	d, err := wav.Duration(path)
+	if err != nil {
+		return 0
+	}
+	return d
+}
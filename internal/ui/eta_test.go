@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpeedTracker(t *testing.T) {
+	type obs struct{ audio, wall time.Duration }
+	tests := []struct {
+		name         string
+		observations []obs
+		audioSeconds float64
+		want         time.Duration
+	}{
+		{"no history", nil, 60, 0},
+		{"first run sets speed", []obs{{60 * time.Second, 10 * time.Second}}, 60, 10 * time.Second},
+		// 0.3*2 + 0.7*6 = 4.8x
+		{"moving average", []obs{{60 * time.Second, 10 * time.Second}, {20 * time.Second, 10 * time.Second}}, 60, 12500 * time.Millisecond},
+		// 0.3*10 + 0.7*4.8 = 6.36x
+		{"three runs", []obs{{60 * time.Second, 10 * time.Second}, {20 * time.Second, 10 * time.Second}, {100 * time.Second, 10 * time.Second}}, 63.6, 10 * time.Second},
+		{"invalid observations ignored", []obs{{0, 5 * time.Second}, {30 * time.Second, 0}, {30 * time.Second, 10 * time.Second}}, 30, 10 * time.Second},
+		{"no audio", []obs{{60 * time.Second, 10 * time.Second}}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tr speedTracker
+			for _, o := range tt.observations {
+				tr.observe(o.audio, o.wall)
+			}
+			got := tr.estimate(tt.audioSeconds)
+			if diff := got - tt.want; diff < -time.Millisecond || diff > time.Millisecond {
+				t.Errorf("estimate(%v) = %v, want %v", tt.audioSeconds, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"blackbox/internal/pathx"
+)
+
+// exportRecord is one row of ExportData output.
+type exportRecord struct {
+	Name            string    `json:"name"`
+	Path            string    `json:"path"`
+	ModifiedAt      time.Time `json:"modified_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	SampleRate      int       `json:"sample_rate"`
+	Channels        int       `json:"channels"`
+	FileSize        int64     `json:"file_size"`
+	Transcript      string    `json:"transcript"`
+	Summary         string    `json:"summary"`
+}
+
+var exportCSVHeader = []string{
+	"name", "path", "modified_at", "duration_seconds", "sample_rate",
+	"channels", "file_size", "transcript", "summary",
+}
+
+// ExportData writes every recording in OutDir with its transcript and summary
+// to outPath, as a JSON array (format "json") or CSV ("csv"). includeAudio
+// adds each recording's bytes base64-encoded ("audio_base64"). Records are
+// written one at a time so large libraries aren't held in memory.
+func (a *App) ExportData(format, outPath string, includeAudio bool) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "json" && format != "csv" {
+		return fmt.Errorf("unknown export format %q (want json or csv)", format)
+	}
+	if strings.TrimSpace(outPath) == "" {
+		return fmt.Errorf("output path required")
+	}
+	recs, err := a.listRecordings()
+	if err != nil {
+		return err
+	}
+	dir := a.settings.Get().transcriptDir()
+
+	if err := os.MkdirAll(pathx.Long(filepath.Dir(outPath)), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(pathx.Long(outPath))
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if format == "json" {
+		err = exportJSON(w, recs, dir, includeAudio)
+	} else {
+		err = exportCSV(w, recs, dir, includeAudio)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(pathx.Long(outPath))
+		return fmt.Errorf("export %s: %w", format, err)
+	}
+	return nil
+}
+
+func newExportRecord(r RecordingInfo, dir string) exportRecord {
+	base := strings.TrimSuffix(r.Name, filepath.Ext(r.Name))
+	transcript, summary := recordingTexts(dir, base)
+	return exportRecord{
+		Name:            r.Name,
+		Path:            r.Path,
+		ModifiedAt:      r.ModifiedAt,
+		DurationSeconds: r.DurationSeconds,
+		SampleRate:      r.SampleRate,
+		Channels:        r.Channels,
+		FileSize:        r.FileSize,
+		Transcript:      transcript,
+		Summary:         summary,
+	}
+}
+
+func exportJSON(w io.Writer, recs []RecordingInfo, dir string, includeAudio bool) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	for i, r := range recs {
+		b, err := json.Marshal(newExportRecord(r, dir))
+		if err != nil {
+			return err
+		}
+		sep := ",\n  "
+		if i == 0 {
+			sep = "\n  "
+		}
+		if _, err := io.WriteString(w, sep); err != nil {
+			return err
+		}
+		if includeAudio {
+			// Stream the audio into the object ahead of the marshalled fields
+			if _, err := io.WriteString(w, `{"audio_base64":"`); err != nil {
+				return err
+			}
+			if err := copyBase64(w, r.Path); err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, `",`); err != nil {
+				return err
+			}
+			b = b[1:]
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "\n]\n")
+	return err
+}
+
+func exportCSV(w io.Writer, recs []RecordingInfo, dir string, includeAudio bool) error {
+	cw := csv.NewWriter(w)
+	header := exportCSVHeader
+	if includeAudio {
+		header = append(header[:len(header):len(header)], "audio_base64")
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range recs {
+		rec := newExportRecord(r, dir)
+		row := []string{
+			rec.Name,
+			rec.Path,
+			rec.ModifiedAt.Format(time.RFC3339),
+			strconv.FormatFloat(rec.DurationSeconds, 'f', 2, 64),
+			strconv.Itoa(rec.SampleRate),
+			strconv.Itoa(rec.Channels),
+			strconv.FormatInt(rec.FileSize, 10),
+			rec.Transcript,
+			rec.Summary,
+		}
+		if includeAudio {
+			// CSV fields can't be streamed, so one recording is held at a time
+			var sb strings.Builder
+			if err := copyBase64(&sb, r.Path); err != nil {
+				return err
+			}
+			row = append(row, sb.String())
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// copyBase64 writes the file at path to w, base64-encoded.
+func copyBase64(w io.Writer, path string) error {
+	f, err := os.Open(pathx.Long(path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	enc := base64.NewEncoder(base64.StdEncoding, w)
+	if _, err := io.Copy(enc, f); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"blackbox/internal/dsp"
+	"blackbox/internal/pathx"
+	"blackbox/internal/wav"
+)
+
+const (
+	// importPeakDBFS is the peak level imported recordings are normalised to.
+	importPeakDBFS = -1.0
+	// importMaxGain stops near-silent sources being amplified into noise (+20 dB).
+	importMaxGain = 10.0
+)
+
+// ImportResult describes a recording brought in by ImportRecording.
+type ImportResult struct {
+	Path         string  `json:"path"`          // standardised 16 kHz mono WAV in OutDir
+	OriginalPath string  `json:"original_path"` // source file, left untouched
+	GainDB       float64 `json:"gain_db"`       // normalisation applied
+}
+
+// ImportRecording converts any audio file ffmpeg (or the WAV reader) can read
+// into a peak-normalised 16 kHz mono WAV in OutDir, ready for whisper. The
+// original is left in place and referenced from a <base>.import.json sidecar.
+// If <base>.wav already exists in OutDir the copy is named <base>_std.wav,
+// then <base>_std2.wav and so on, so nothing is overwritten.
+func (a *App) ImportRecording(srcPath string) (ImportResult, error) {
+	if strings.TrimSpace(srcPath) == "" {
+		return ImportResult{}, errors.New("source path required")
+	}
+	if _, err := os.Stat(pathx.Long(srcPath)); err != nil {
+		return ImportResult{}, err
+	}
+	outDir := a.settings.Get().OutDir
+	if err := os.MkdirAll(pathx.Long(outDir), 0755); err != nil {
+		return ImportResult{}, err
+	}
+	base := importBase(outDir, strings.TrimSuffix(filepath.Base(srcPath), filepath.Ext(srcPath)))
+	dest := filepath.Join(outDir, base+".wav")
+
+	// Pass one: 16 kHz mono in a temporary file
+	std, cleanup, err := standardiseForImport(srcPath)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("convert to 16 kHz mono: %w", err)
+	}
+	defer cleanup()
+
+	// Pass two: measure the peak and write the normalised copy
+	gain, err := importGain(std)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	if err := writeWithGain(std, dest, gain); err != nil {
+		_ = os.Remove(pathx.Long(dest))
+		return ImportResult{}, fmt.Errorf("write %s: %w", dest, err)
+	}
+
+	abs, err := filepath.Abs(srcPath)
+	if err != nil {
+		abs = srcPath
+	}
+	res := ImportResult{Path: dest, OriginalPath: abs, GainDB: 20 * math.Log10(gain)}
+	sidecar, err := json.MarshalIndent(struct {
+		ImportResult
+		ImportedAt time.Time `json:"imported_at"`
+	}{res, time.Now()}, "", "  ")
+	if err != nil {
+		return ImportResult{}, err
+	}
+	if err := os.WriteFile(pathx.Long(filepath.Join(outDir, base+".import.json")), sidecar, 0644); err != nil {
+		return ImportResult{}, fmt.Errorf("write import sidecar: %w", err)
+	}
+	return res, nil
+}
+
+// importBase returns the first of base, base_std, base_std2, ... for which
+// neither the WAV nor the .import.json sidecar exists in outDir.
+func importBase(outDir, base string) string {
+	taken := func(name string) bool {
+		for _, ext := range []string{".wav", ".import.json"} {
+			if _, err := os.Stat(pathx.Long(filepath.Join(outDir, name+ext))); err == nil {
+				return true
+			}
+		}
+		return false
+	}
+	if !taken(base) {
+		return base
+	}
+	name := base + "_std"
+	for i := 2; taken(name); i++ {
+		name = fmt.Sprintf("%s_std%d", base, i)
+	}
+	return name
+}
+
+// standardiseForImport writes a temporary 16 kHz mono 16-bit copy of src.
+// 16-bit PCM WAVs are converted in-process; anything else goes through ffmpeg.
+func standardiseForImport(src string) (string, func(), error) {
+	if isWAVFile(src) {
+		if r, err := wav.OpenReader(src); err == nil {
+			defer r.Close()
+			if r.AudioFormat() == 1 && r.BitsPerSample() == 16 {
+				tmpDir, err := os.MkdirTemp("", "blackbox-import-")
+				if err != nil {
+					return "", nil, err
+				}
+				cleanup := func() { _ = os.RemoveAll(tmpDir) }
+				out := filepath.Join(tmpDir, "standard.wav")
+				if err := writeWhisperWAV(r, out); err != nil {
+					cleanup()
+					return "", nil, err
+				}
+				return out, cleanup, nil
+			}
+		}
+	}
+	return decodeForTranscription(src)
+}
+
+// importGain returns the gain that brings the WAV's peak to importPeakDBFS,
+// capped at importMaxGain. Silent files get unity gain.
+func importGain(path string) (float64, error) {
+	r, err := wav.OpenReader(path)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	var peak float64
+	buf := make([]byte, 64*1024)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			_, p, _ := dsp.MeasureS16LE(buf[:n])
+			peak = max(peak, p)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return 0, readErr
+		}
+	}
+	if peak == 0 {
+		return 1, nil
+	}
+	return min(math.Pow(10, importPeakDBFS/20)/peak, importMaxGain), nil
+}
+
+// writeWithGain copies the 16-bit WAV at src to dst, scaling samples by gain.
+func writeWithGain(src, dst string, gain float64) error {
+	r, err := wav.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	w, err := wav.NewWriter(dst, r.SampleRate(), r.Channels(), r.BitsPerSample())
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 64*1024)
+	var samples []int16
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			samples = dsp.DecodeS16LE(samples, buf[:n])
+			dsp.Gain(samples, gain)
+			if _, err := w.Write(dsp.EncodeS16LE(buf[:0], samples)); err != nil {
+				w.Close()
+				return err
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			w.Close()
+			return readErr
+		}
+	}
+	return w.Close()
+}
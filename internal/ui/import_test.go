@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"blackbox/internal/dsp"
+	"blackbox/internal/wav"
+)
+
+func TestImportRecording(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	t.Setenv("LOOPBACK_NOTES_FFMPEG_BIN", filepath.Join(t.TempDir(), "missing-ffmpeg.exe"))
+	src := filepath.Join(t.TempDir(), "interview.wav")
+	// 48 kHz stereo at -12 dBFS: neither whisper's format nor the import level
+	writeTestWAV(t, src, 48000, 2, sine(48000, 2, 1, 0.25))
+	before, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := a.ImportRecording(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := filepath.Base(res.Path), "interview.wav"; got != want {
+		t.Errorf("path = %s, want %s", got, want)
+	}
+
+	r, err := wav.OpenReader(res.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if r.SampleRate() != 16000 || r.Channels() != 1 || r.BitsPerSample() != 16 {
+		t.Fatalf("format = %d Hz, %d ch, %d-bit, want 16000 Hz mono 16-bit",
+			r.SampleRate(), r.Channels(), r.BitsPerSample())
+	}
+	if got := r.Duration().Seconds(); math.Abs(got-1) > 0.01 {
+		t.Errorf("duration = %.3fs, want 1s", got)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, peak, _ := dsp.MeasureS16LE(data)
+	if got := 20 * math.Log10(peak); math.Abs(got-importPeakDBFS) > 0.1 {
+		t.Errorf("peak = %.2f dBFS, want %.1f", got, importPeakDBFS)
+	}
+	if math.Abs(res.GainDB-11) > 0.1 {
+		t.Errorf("gain = %.2f dB, want about 11", res.GainDB)
+	}
+
+	after, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Error("source file was modified")
+	}
+	sidecar := filepath.Join(filepath.Dir(res.Path), "interview.import.json")
+	if _, err := os.Stat(sidecar); err != nil {
+		t.Errorf("sidecar: %v", err)
+	}
+}
+
+func TestImportRecordingUniqueName(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	src := filepath.Join(t.TempDir(), "call.wav")
+	writeTestWAV(t, src, 16000, 1, sine(16000, 1, 0.2, 0.5))
+
+	// Each import of the same source gets its own WAV and sidecar
+	for _, want := range []string{"call", "call_std", "call_std2", "call_std3"} {
+		res, err := a.ImportRecording(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := filepath.Base(res.Path); got != want+".wav" {
+			t.Errorf("path = %s, want %s.wav", got, want)
+		}
+		if _, err := os.Stat(filepath.Join(filepath.Dir(res.Path), want+".import.json")); err != nil {
+			t.Errorf("sidecar: %v", err)
+		}
+	}
+}
+
+func TestImportBase(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		want     string
+	}{
+		{"free", nil, "talk"},
+		{"wav taken", []string{"talk.wav"}, "talk_std"},
+		{"sidecar taken", []string{"talk.import.json"}, "talk_std"},
+		{"std taken", []string{"talk.wav", "talk_std.wav"}, "talk_std2"},
+		{"std sidecar taken", []string{"talk.wav", "talk_std.import.json"}, "talk_std2"},
+		{"gap", []string{"talk.wav", "talk_std.wav", "talk_std3.wav"}, "talk_std2"},
+		{"other base", []string{"talks.wav", "talk.txt"}, "talk"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.existing {
+				if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := importBase(dir, "talk"); got != tt.want {
+				t.Errorf("importBase = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
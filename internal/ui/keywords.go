@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"blackbox/internal/pathx"
+)
+
+const defaultKeywordCount = 10
+
+// GetTranscriptKeywords returns the top n keywords of a transcript by term
+// frequency, ignoring stopwords. n <= 0 uses the default of 10.
+func (a *App) GetTranscriptKeywords(txtPath string, n int) ([]string, error) {
+	if strings.TrimSpace(txtPath) == "" {
+		return nil, errors.New("txt path required")
+	}
+	b, err := os.ReadFile(pathx.Long(txtPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	if n <= 0 {
+		n = defaultKeywordCount
+	}
+	return topKeywords(stripAppendedSummary(string(b)), n), nil
+}
+
+// whisperMarker matches whisper's non-speech annotations such as
+// [BLANK_AUDIO], [MUSIC] or (upbeat music).
+var whisperMarker = regexp.MustCompile(`\[[^\]\n]*\]|\([^)\n]*\)`)
+
+// topKeywords counts words of three or more letters that aren't stopwords
+// and returns the n most frequent, breaking ties alphabetically. Whisper's
+// markers are removed first so they don't count as words.
+func topKeywords(text string, n int) []string {
+	counts := make(map[string]int)
+	text = whisperMarker.ReplaceAllString(text, " ")
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	for _, w := range words {
+		w = strings.Trim(w, "'")
+		w = strings.TrimSuffix(w, "'s")
+		if len([]rune(w)) < 3 || stopwords[w] || isNumber(w) {
+			continue
+		}
+		counts[w]++
+	}
+
+	keywords := make([]string, 0, len(counts))
+	for w := range counts {
+		keywords = append(keywords, w)
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if counts[keywords[i]] != counts[keywords[j]] {
+			return counts[keywords[i]] > counts[keywords[j]]
+		}
+		return keywords[i] < keywords[j]
+	})
+	if len(keywords) > n {
+		keywords = keywords[:n]
+	}
+	return keywords
+}
+
+func isNumber(w string) bool {
+	for _, r := range w {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// stopwords are common English words and filler that carry no topic information.
+var stopwords = func() map[string]bool {
+	list := `about above after again against all also am an and any are aren't as at
+be because been before being below between both but by can can't cannot could couldn't
+did didn't do does doesn't doing don't down during each few for from further get gets
+getting go goes going gonna got had hadn't has hasn't have haven't having he he'd he'll
+her here hers herself him himself his how i'd i'll i'm i've if in into is isn't it it'll
+it's its itself just know let let's like lot make many may maybe me mean might more most
+much must my myself need no nor not now of off okay on once one only or other ought our
+ours ourselves out over own really right said same say says see she she'd she'll should
+shouldn't so some something such sure take than that that's the their theirs them
+themselves then there there's these they they'd they'll they're they've thing things
+think this those though through to too um uh up us very want was wasn't way we we'd
+we'll we're we've well were weren't what what's when where which while who whom why
+will with won't would wouldn't yeah yes yet you you'd you'll you're you've your yours
+yourself yourselves actually kind sort wanna`
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(list) {
+		m[w] = true
+	}
+	return m
+}()
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestTopKeywords(t *testing.T) {
+	const transcript = `[BLANK_AUDIO]
+ So the budget review is on Friday. The budget covers the new audio interface
+ and the microphone upgrade. [MUSIC] (upbeat music)
+ Um, yeah, the audio interface budget was approved in 2024, and we'll order the
+ interface this week. It's the team's call on the microphone's stand.
+[BLANK_AUDIO]`
+
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{3, []string{"budget", "interface", "audio"}},
+		{5, []string{"budget", "interface", "audio", "microphone", "approved"}},
+		{100, []string{"budget", "interface", "audio", "microphone", "approved", "call", "covers", "friday", "new", "order", "review", "stand", "team", "upgrade", "week"}},
+	}
+	for _, tt := range tests {
+		if got := topKeywords(transcript, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("topKeywords(n=%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestGetTranscriptKeywordsIgnoresSummary(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	txt := filepath.Join(t.TempDir(), "call.txt")
+	content := "Deployment deployment rollback." + transcriptSummaryDelimiter + "Summary summary summary summary."
+	if err := os.WriteFile(txt, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := a.GetTranscriptKeywords(txt, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"deployment", "rollback"}; !slices.Equal(got, want) {
+		t.Errorf("keywords = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"blackbox/internal/pathx"
+)
+
+// defaultLanguage is passed to whisper when the Language setting is empty.
+const defaultLanguage = "en"
+
+// whisperLanguage matches whisper's -l values: ISO 639-1/3 codes or "auto".
+var whisperLanguage = regexp.MustCompile(`^(?:[a-z]{2,3}|auto)$`)
+
+// languageSidecar is the file next to a recording holding its language override.
+func languageSidecar(audioPath string) string {
+	return strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + ".lang"
+}
+
+// SetRecordingLanguage sets the transcription language for one recording
+// (e.g. "de", or "auto" to let whisper detect it), overriding the Language
+// setting. An empty lang clears the override.
+func (a *App) SetRecordingLanguage(audioPath, lang string) error {
+	if strings.TrimSpace(audioPath) == "" {
+		return errors.New("recording path required")
+	}
+	if _, err := os.Stat(pathx.Long(audioPath)); err != nil {
+		return err
+	}
+	sidecar := languageSidecar(audioPath)
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		if err := os.Remove(pathx.Long(sidecar)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if !whisperLanguage.MatchString(lang) {
+		return fmt.Errorf("invalid language %q (want a code such as en or de, or auto)", lang)
+	}
+	return os.WriteFile(pathx.Long(sidecar), []byte(lang+"\n"), 0644)
+}
+
+// GetRecordingLanguage returns the language a recording is transcribed in:
+// its override if set, else the Language setting.
+func (a *App) GetRecordingLanguage(audioPath string) string {
+	return recordingLanguage(audioPath, a.settings.Get())
+}
+
+func recordingLanguage(audioPath string, cfg UISettings) string {
+	if b, err := os.ReadFile(pathx.Long(languageSidecar(audioPath))); err == nil {
+		if lang := strings.TrimSpace(string(b)); whisperLanguage.MatchString(lang) {
+			return lang
+		}
+	}
+	if cfg.Language != "" {
+		return cfg.Language
+	}
+	return defaultLanguage
+}
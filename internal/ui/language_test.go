@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// TestRecordingLanguageReachesWhisper checks the language passed to whisper
+// as -l comes from a recording's .lang override before the Language setting.
+func TestRecordingLanguageReachesWhisper(t *testing.T) {
+	tests := []struct {
+		name     string
+		setting  string
+		override string // SetRecordingLanguage value, "" for none
+		sidecar  string // raw .lang contents, written when override is ""
+		want     string
+	}{
+		{"default", "", "", "", "en"},
+		{"setting", "fr", "", "", "fr"},
+		{"override", "fr", "de", "", "de"},
+		{"override auto", "", "Auto", "", "auto"},
+		{"invalid sidecar ignored", "fr", "", "not a language\n", "fr"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t, UISettings{Language: tt.setting})
+			lastArgs := useFakeWhisper(t, a)
+			wavPath := filepath.Join(t.TempDir(), "meeting.wav")
+			writeTestWAV(t, wavPath, 16000, 1, sine(16000, 1, 1, 0.5))
+			if tt.override != "" {
+				if err := a.SetRecordingLanguage(wavPath, tt.override); err != nil {
+					t.Fatal(err)
+				}
+			} else if tt.sidecar != "" {
+				if err := os.WriteFile(languageSidecar(wavPath), []byte(tt.sidecar), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := a.GetRecordingLanguage(wavPath); got != tt.want {
+				t.Errorf("GetRecordingLanguage = %q, want %q", got, tt.want)
+			}
+			if _, err := a.Transcribe(wavPath); err != nil {
+				t.Fatal(err)
+			}
+			args := lastArgs()
+			i := slices.Index(args, "-l")
+			if i < 0 || i+1 >= len(args) || args[i+1] != tt.want {
+				t.Errorf("whisper args %q, want -l %s", args, tt.want)
+			}
+		})
+	}
+}
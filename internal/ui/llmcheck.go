@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TestLLMConnection sends a tiny probe request to the "remote" or "local"
+// endpoint and reports a specific failure (config, network, auth or
+// model-not-found). For "local", llama-server is started for the probe and
+// stopped again if it wasn't already running.
+func (a *App) TestLLMConnection(which string) error {
+	switch strings.ToLower(strings.TrimSpace(which)) {
+	case "remote":
+		cfg, err := a.loadLLMConfig("./configs/remote.json")
+		if err != nil {
+			return fmt.Errorf("remote config: %w", err)
+		}
+		return a.probeLLM(cfg)
+	case "local":
+		cfg, err := a.loadLLMConfig("./configs/local.json")
+		if err != nil {
+			return fmt.Errorf("local config: %w", err)
+		}
+		if !a.isLlamaServerRunning() {
+			if err := a.startLlamaServer(); err != nil {
+				return fmt.Errorf("failed to start llama-server: %w", err)
+			}
+			defer a.stopLlamaServer()
+		}
+		return a.probeLLM(&llmConfig{BaseURL: "http://127.0.0.1:8080", APIKey: cfg.APIKey, Model: "local"})
+	default:
+		return fmt.Errorf("unknown endpoint %q (want \"remote\" or \"local\")", which)
+	}
+}
+
+// probeLLM sends a minimal request through the configured provider and classifies any failure.
+func (a *App) probeLLM(cfg *llmConfig) error {
+	_, err := newProvider(cfg, 16).Summarise(context.Background(), "", "ping")
+	return classifyLLMError(cfg.endpointURL(), err)
+}
+
+// classifyLLMError turns a makeOpenAIRequest error into a user-facing diagnosis.
+func classifyLLMError(endpoint string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var statusErr *apiStatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden:
+			return fmt.Errorf("authentication failed (HTTP %d): check api_key", statusErr.StatusCode)
+		case statusErr.StatusCode == http.StatusNotFound || isModelNotFound(statusErr.Body):
+			return fmt.Errorf("model or deployment not found (HTTP %d): check model/azure_deployment and base_url: %s",
+				statusErr.StatusCode, strings.TrimSpace(statusErr.Body))
+		default:
+			return fmt.Errorf("endpoint returned HTTP %d: %s", statusErr.StatusCode, strings.TrimSpace(statusErr.Body))
+		}
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("network error reaching %s: %w", endpoint, urlErr.Err)
+	}
+	return err
+}
+
+// isModelNotFound reports whether an error body describes an unknown model.
+// OpenAI-compatible servers often return 400 rather than 404 for this.
+func isModelNotFound(body string) bool {
+	b := strings.ToLower(body)
+	return strings.Contains(b, "model_not_found") ||
+		(strings.Contains(b, "model") && (strings.Contains(b, "does not exist") || strings.Contains(b, "not found")))
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTestLLMConnection(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string // substring, "" for success
+	}{
+		{"ok", http.StatusOK, `{"choices":[{"message":{"content":"pong"}}]}`, ""},
+		{"unauthorized", http.StatusUnauthorized, `{"error":{"message":"Incorrect API key"}}`, "authentication failed (HTTP 401)"},
+		{"forbidden", http.StatusForbidden, `{"error":{"message":"no access"}}`, "authentication failed (HTTP 403)"},
+		{"not found", http.StatusNotFound, `{"error":{"message":"Resource not found"}}`, "model or deployment not found (HTTP 404)"},
+		{"unknown model as 400", http.StatusBadRequest, `{"error":{"code":"model_not_found","message":"The model 'gpt-x' does not exist"}}`, "model or deployment not found (HTTP 400)"},
+		{"other status", http.StatusTeapot, `short and stout`, "endpoint returned HTTP 418: short and stout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+			a := newTestApp(t, UISettings{})
+			writeRemoteConfig(t, llmConfig{BaseURL: srv.URL, APIKey: "k", Model: "gpt-x"})
+
+			err := a.TestLLMConnection("remote")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTestLLMConnectionNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close() // nothing listening any more
+
+	a := newTestApp(t, UISettings{})
+	writeRemoteConfig(t, llmConfig{BaseURL: url, APIKey: "k", Model: "m"})
+	err := a.TestLLMConnection("remote")
+	if err == nil || !strings.HasPrefix(err.Error(), "network error reaching "+url) {
+		t.Errorf("err = %v, want a network error for %s", err, url)
+	}
+}
+
+func TestTestLLMConnectionConfig(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	if err := a.TestLLMConnection("remote"); err == nil || !strings.HasPrefix(err.Error(), "remote config") {
+		t.Errorf("missing config: err = %v", err)
+	}
+	if err := a.TestLLMConnection("cloud"); err == nil || !strings.Contains(err.Error(), "unknown endpoint") {
+		t.Errorf("unknown endpoint: err = %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"blackbox/internal/execx"
+)
+
+// llamaServerBin is the bundled llama.cpp server used for local AI.
+const llamaServerBin = "./llamacpp-bin/llama-server.exe"
+
+var (
+	errLlamaModelNotSet = errors.New("no local AI model selected; choose a .gguf model in settings")
+	errLlamaBinMissing  = errors.New("llama-server.exe not found in llamacpp-bin directory")
+)
+
+// ValidateLocalAISetup checks that local AI summarisation can start: a model
+// is selected and looks like a complete GGUF file, llama-server is present
+// and configs/local.json loads. It returns the first problem found, so the
+// settings screen can show it before a summary is attempted.
+func (a *App) ValidateLocalAISetup() error {
+	model := a.settings.Get().LlamaModel
+	if model == "" {
+		return errLlamaModelNotSet
+	}
+	if _, err := os.Stat(model); err != nil {
+		return fmt.Errorf("local AI model not found: %w", err)
+	}
+	if err := execx.ValidateModel(model); err != nil {
+		return fmt.Errorf("local AI model: %w", err)
+	}
+	if _, err := os.Stat(llamaServerBin); err != nil {
+		return errLlamaBinMissing
+	}
+	if _, err := a.loadLLMConfig("./configs/local.json"); err != nil {
+		return fmt.Errorf("local config: %w", err)
+	}
+	return nil
+}
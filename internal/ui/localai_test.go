@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateLocalAISetup(t *testing.T) {
+	const goodConfig = `{"base_url": "http://127.0.0.1:8080/v1", "model": "local", "api_key": "local"}`
+	tests := []struct {
+		name    string
+		model   string // "", "missing", "truncated" or "ok"
+		bin     bool
+		config  string // contents of configs/local.json, "" for none
+		wantIs  error
+		wantErr string // substring, "" with wantIs nil for success
+	}{
+		{"no model selected", "", true, goodConfig, errLlamaModelNotSet, ""},
+		{"model file missing", "missing", true, goodConfig, nil, "local AI model not found"},
+		{"truncated model", "truncated", true, goodConfig, nil, "corrupt or incomplete"},
+		{"no llama-server", "ok", false, goodConfig, errLlamaBinMissing, ""},
+		{"no local config", "ok", true, "", nil, "local config"},
+		{"incomplete local config", "ok", true, `{"base_url": "http://127.0.0.1:8080/v1"}`, nil, "missing required fields"},
+		{"ready", "ok", true, goodConfig, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t, UISettings{})
+			dir, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			write := func(path string, b []byte) {
+				t.Helper()
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, b, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var model string
+			switch tt.model {
+			case "missing":
+				model = filepath.Join(dir, "models", "gone.gguf")
+			case "truncated":
+				model = filepath.Join(dir, "models", "partial.gguf")
+				write(model, []byte("GGUF"))
+			case "ok":
+				model = filepath.Join(dir, "models", "model.gguf")
+				b := make([]byte, 1<<20)
+				copy(b, "GGUF")
+				write(model, b)
+			}
+			s := a.settings.Get()
+			s.LlamaModel = model
+			if err := a.settings.Save(s); err != nil {
+				t.Fatal(err)
+			}
+			if tt.bin {
+				write(llamaServerBin, nil)
+			}
+			if tt.config != "" {
+				write(filepath.Join("configs", "local.json"), []byte(tt.config))
+			}
+
+			err = a.ValidateLocalAISetup()
+			switch {
+			case tt.wantIs != nil:
+				if !errors.Is(err, tt.wantIs) {
+					t.Errorf("ValidateLocalAISetup = %v, want %v", err, tt.wantIs)
+				}
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ValidateLocalAISetup = %v, want error containing %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Errorf("ValidateLocalAISetup: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"blackbox/internal/dsp"
+	"blackbox/internal/pathx"
+	"blackbox/internal/wav"
+)
+
+// loudnessFloorLUFS is reported for silence, where loudness is -Inf (which
+// JSON can't carry). It matches the R128 absolute gate.
+const loudnessFloorLUFS = -70.0
+
+// RecordingLoudness is a recording with its integrated loudness.
+type RecordingLoudness struct {
+	RecordingInfo
+	LUFS float64 `json:"lufs"`
+}
+
+// loudnessCache keeps measurements for unchanged files so repeat listings
+// don't re-read every recording.
+type loudnessCache struct {
+	mu      sync.Mutex
+	entries map[string]loudnessEntry
+}
+
+type loudnessEntry struct {
+	size    int64
+	modTime time.Time
+	lufs    float64
+}
+
+// MeasureLoudness returns the integrated loudness of a 16-bit recording in
+// LUFS (EBU R128), or -70 for silence. Results are cached until the file changes.
+func (a *App) MeasureLoudness(wavPath string) (float64, error) {
+	info, err := os.Stat(pathx.Long(wavPath))
+	if err != nil {
+		return 0, err
+	}
+	a.loudness.mu.Lock()
+	e, ok := a.loudness.entries[wavPath]
+	a.loudness.mu.Unlock()
+	if ok && e.size == info.Size() && e.modTime.Equal(info.ModTime()) {
+		return e.lufs, nil
+	}
+
+	r, err := wav.OpenReader(wavPath)
+	if err != nil {
+		return 0, fmt.Errorf("open wav: %w", err)
+	}
+	defer r.Close()
+	if r.BitsPerSample() != 16 {
+		return 0, fmt.Errorf("loudness measurement needs 16-bit PCM, got %d-bit", r.BitsPerSample())
+	}
+	lufs, err := integratedLoudness(r, int(r.SampleRate()), int(r.Channels()))
+	if err != nil {
+		return 0, err
+	}
+
+	a.loudness.mu.Lock()
+	if a.loudness.entries == nil {
+		a.loudness.entries = make(map[string]loudnessEntry)
+	}
+	a.loudness.entries[wavPath] = loudnessEntry{size: info.Size(), modTime: info.ModTime(), lufs: lufs}
+	a.loudness.mu.Unlock()
+	return lufs, nil
+}
+
+// ListRecordingsByLoudness returns recordings in OutDir with their loudness,
+// filtered to [minLUFS, maxLUFS] (nil bounds are open), newest first. Use a
+// maxLUFS of e.g. -30 to find recordings that are too quiet, or a minLUFS of
+// -10 for ones that are too loud. Files that can't be measured are skipped.
+func (a *App) ListRecordingsByLoudness(minLUFS, maxLUFS *float64) ([]RecordingLoudness, error) {
+	recs, err := a.listRecordings()
+	if err != nil {
+		return nil, err
+	}
+	matched := []RecordingLoudness{}
+	for _, rec := range recs {
+		if rec.BitsPerSample != 16 {
+			continue
+		}
+		lufs, err := a.MeasureLoudness(rec.Path)
+		if err != nil {
+			continue
+		}
+		if minLUFS != nil && lufs < *minLUFS {
+			continue
+		}
+		if maxLUFS != nil && lufs > *maxLUFS {
+			continue
+		}
+		matched = append(matched, RecordingLoudness{RecordingInfo: rec, LUFS: lufs})
+	}
+	return matched, nil
+}
+
+// integratedLoudness measures S16LE samples from src.
+func integratedLoudness(src io.Reader, sampleRate, channels int) (float64, error) {
+	meter := dsp.NewLoudnessMeter(sampleRate, channels)
+	buf := make([]byte, 4096*2*max(channels, 1))
+	var samples []int16
+	for {
+		n, err := io.ReadFull(src, buf)
+		if n > 0 {
+			samples = dsp.DecodeS16LE(samples, buf[:n])
+			meter.Add(samples)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("read samples: %w", err)
+		}
+	}
+	lufs := meter.Integrated()
+	if math.IsInf(lufs, -1) || lufs < loudnessFloorLUFS {
+		return loudnessFloorLUFS, nil
+	}
+	return lufs, nil
+}
@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// summaryMaxTokens is the completion budget requested for each summary.
+const summaryMaxTokens = 2000
+
+// completeFunc sends messages to the configured LLM, streaming deltas to
+// onDelta when it is non-nil, and returns the full reply.
+type completeFunc func(messages []chatMessage, onDelta func(string)) (string, error)
+
+// chunkTranscript splits text into pieces of at most maxTokens (estimated),
+// preferring paragraph, then sentence, then word boundaries. maxTokens <= 0
+// or text that already fits returns text as a single chunk.
+func chunkTranscript(text string, maxTokens int) []string {
+	text = strings.TrimSpace(text)
+	if maxTokens <= 0 || estimateTokens(text) <= maxTokens {
+		return []string{text}
+	}
+	limit := maxTokens * charsPerToken
+	var chunks []string
+	for len(text) > limit {
+		cut := splitPoint(text[:limit])
+		chunks = append(chunks, strings.TrimSpace(text[:cut]))
+		text = strings.TrimLeftFunc(text[cut:], unicode.IsSpace)
+	}
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
+
+// splitPoint returns where to end a chunk within s: after the last paragraph
+// break, sentence end or space in its second half, else at a rune boundary.
+func splitPoint(s string) int {
+	half := len(s) / 2
+	if i := strings.LastIndex(s, "\n\n"); i > half {
+		return i + 2
+	}
+	for _, sep := range []string{". ", "? ", "! ", "\n"} {
+		if i := strings.LastIndex(s, sep); i > half {
+			return i + len(sep)
+		}
+	}
+	if i := strings.LastIndexFunc(s, unicode.IsSpace); i > half {
+		return i + 1
+	}
+	// Back off a rune cut in half by the byte limit
+	cut := len(s)
+	if r, size := utf8.DecodeLastRuneInString(s); r == utf8.RuneError && size <= 1 {
+		for cut > 1 && !utf8.RuneStart(s[cut-1]) {
+			cut--
+		}
+		cut--
+	}
+	return max(cut, 1)
+}
+
+// localChunkTokens returns the transcript chunk size that fits llama-server's
+// context window alongside the prompt, any previous summary and the reply.
+func localChunkTokens(contextTokens int, prompt, previous string) int {
+	const margin = 512 // chat template and instructions added around the chunk
+	n := contextTokens - estimateTokens(prompt) - estimateTokens(previous) - summaryMaxTokens - margin
+	return max(n, 1024)
+}
+
+// summariseChunks summarises chunks with prompt. A single chunk is sent as-is;
+// otherwise each chunk is summarised separately and the partial summaries are
+// combined in a final pass, which is the only one streamed to onDelta. When
+// the partial summaries together exceed maxTokens they are first combined in
+// batches, repeatedly, until they fit one request.
+func summariseChunks(complete completeFunc, prompt string, chunks []string, maxTokens int, previous string, onDelta func(string)) (string, error) {
+	if len(chunks) <= 1 {
+		transcript := ""
+		if len(chunks) == 1 {
+			transcript = chunks[0]
+		}
+		messages := []chatMessage{{Role: "system", Content: prompt}, {Role: "user", Content: transcript}}
+		return complete(withPreviousSummary(messages, previous), onDelta)
+	}
+
+	partials := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		partial, err := complete([]chatMessage{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: fmt.Sprintf("This is part %d of %d of a longer transcript. Summarise this part only; the parts will be combined afterwards.\n\n%s", i+1, len(chunks), chunk)},
+		}, nil)
+		if err != nil {
+			return "", fmt.Errorf("part %d/%d: %w", i+1, len(chunks), err)
+		}
+		partials[i] = strings.TrimSpace(partial)
+	}
+
+	for maxTokens > 0 && len(partials) > 1 && estimateTokens(joinPartSummaries(partials)) > maxTokens {
+		var err error
+		if partials, err = reducePartSummaries(complete, prompt, partials, maxTokens); err != nil {
+			return "", err
+		}
+	}
+
+	messages := []chatMessage{
+		{Role: "system", Content: prompt},
+		{Role: "user", Content: fmt.Sprintf("The transcript was too long to summarise at once, so it was split into %d parts and each part was summarised. Combine these part summaries into a single summary of the whole transcript, following the instructions above.\n\n%s", len(chunks), joinPartSummaries(partials))},
+	}
+	summary, err := complete(withPreviousSummary(messages, previous), onDelta)
+	if err != nil {
+		return "", fmt.Errorf("combining %d part summaries: %w", len(chunks), err)
+	}
+	return summary, nil
+}
+
+// reducePartSummaries combines consecutive partial summaries in batches that
+// fit maxTokens. Every batch but the last holds at least two summaries, so
+// each round returns fewer than it was given.
+func reducePartSummaries(complete completeFunc, prompt string, partials []string, maxTokens int) ([]string, error) {
+	var batches [][]string
+	var batch []string
+	for _, p := range partials {
+		if len(batch) >= 2 && estimateTokens(joinPartSummaries(append(batch[:len(batch):len(batch)], p))) > maxTokens {
+			batches = append(batches, batch)
+			batch = nil
+		}
+		batch = append(batch, p)
+	}
+	batches = append(batches, batch)
+
+	reduced := make([]string, 0, len(batches))
+	for i, b := range batches {
+		if len(b) == 1 {
+			reduced = append(reduced, b[0])
+			continue
+		}
+		s, err := complete([]chatMessage{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: fmt.Sprintf("These are summaries of consecutive parts of a longer transcript. Combine them into one summary of these parts only; it will be combined with the rest afterwards.\n\n%s", joinPartSummaries(b))},
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("combining part summaries (batch %d/%d): %w", i+1, len(batches), err)
+		}
+		reduced = append(reduced, strings.TrimSpace(s))
+	}
+	return reduced, nil
+}
+
+// joinPartSummaries formats partial summaries as numbered sections.
+func joinPartSummaries(partials []string) string {
+	var sb strings.Builder
+	for i, p := range partials {
+		fmt.Fprintf(&sb, "### Part %d\n\n%s\n\n", i+1, p)
+	}
+	return sb.String()
+}
+
+// chunkedSummaryNote is prepended to summaries built from several parts.
+func chunkedSummaryNote(parts int) string {
+	return fmt.Sprintf("> Note: the transcript was summarised in %d parts and then combined.\n\n", parts)
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunkTranscript(t *testing.T) {
+	sentence := "The quick brown fox jumps over the lazy dog. "
+	long := strings.Repeat(sentence, 200)
+	tests := []struct {
+		name      string
+		text      string
+		maxTokens int
+		wantN     int // 0 means more than one
+	}{
+		{"disabled", long, 0, 1},
+		{"fits", "short text", 100, 1},
+		{"trimmed", "  short  ", 100, 1},
+		{"split", long, 500, 0},
+		{"no spaces", strings.Repeat("x", 5000), 100, 0},
+		{"multibyte", strings.Repeat("é", 3000), 100, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := chunkTranscript(tt.text, tt.maxTokens)
+			if tt.wantN > 0 && len(chunks) != tt.wantN {
+				t.Fatalf("got %d chunks, want %d", len(chunks), tt.wantN)
+			}
+			if tt.wantN == 0 && len(chunks) < 2 {
+				t.Fatalf("got %d chunks, want several", len(chunks))
+			}
+			var total int
+			for i, c := range chunks {
+				if tt.maxTokens > 0 && estimateTokens(c) > tt.maxTokens {
+					t.Errorf("chunk %d has %d tokens, over %d", i, estimateTokens(c), tt.maxTokens)
+				}
+				if !utf8.ValidString(c) {
+					t.Errorf("chunk %d is not valid UTF-8", i)
+				}
+				total += len(strings.Join(strings.Fields(c), ""))
+			}
+			if want := len(strings.Join(strings.Fields(tt.text), "")); total != want {
+				t.Errorf("chunks hold %d non-space bytes, want %d", total, want)
+			}
+		})
+	}
+}
+
+func TestSplitPoint(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"paragraph", "aaaaaaaaaaaa\n\nbb. cc", 14},
+		{"sentence", "aaaaaaa bbbbbb. ccccc", 16},
+		{"question", "aaaaaaa bbbbbb? ccccc", 16},
+		{"space", "aaaaaaa bbbbbb ccccc", 15},
+		{"early boundary ignored", "a. bbbbbbbbbbbbbbbbbbbb", 23},
+		{"split rune", "aaaa\xc3", 4},
+		{"whole rune", "aaaé", 5},
+	}
+	for _, tt := range tests {
+		if got := splitPoint(tt.s); got != tt.want {
+			t.Errorf("%s: splitPoint(%q) = %d, want %d", tt.name, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestSummariseChunksReducesOversizedParts(t *testing.T) {
+	const maxTokens = 100
+	var calls, final int
+	complete := func(messages []chatMessage, onDelta func(string)) (string, error) {
+		calls++
+		user := messages[len(messages)-1].Content
+		if estimateTokens(user) > maxTokens*2 {
+			t.Errorf("request %d has %d tokens", calls, estimateTokens(user))
+		}
+		if strings.HasPrefix(user, "The transcript was too long") {
+			final++
+			return "final", nil
+		}
+		// Each partial summary is a third of the budget, so they can't all be combined at once
+		return strings.Repeat("s", maxTokens*charsPerToken/3), nil
+	}
+	chunks := make([]string, 12)
+	for i := range chunks {
+		chunks[i] = fmt.Sprintf("chunk %d", i)
+	}
+	got, err := summariseChunks(complete, "prompt", chunks, maxTokens, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "final" || final != 1 {
+		t.Fatalf("got %q after %d final passes", got, final)
+	}
+	if calls <= len(chunks)+1 {
+		t.Errorf("made %d requests, expected intermediate combines", calls)
+	}
+}
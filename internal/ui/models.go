@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// defaultWhisperModel is used when no model is configured.
+const defaultWhisperModel = "ggml-base.en.bin"
+
+func whisperModelDir() string {
+	return getenvDefault("LOOPBACK_NOTES_MODELS", "./models")
+}
+
+// whisperModelPath resolves a configured model to a path. Bare file names are
+// looked up in the models directory; empty selects the default model.
+func whisperModelPath(model string) string {
+	model = strings.TrimSpace(model)
+	if model == "" {
+		model = defaultWhisperModel
+	}
+	if filepath.IsAbs(model) || strings.ContainsAny(model, `/\`) {
+		return model
+	}
+	return filepath.Join(whisperModelDir(), model)
+}
+
+// ListWhisperModels returns the paths of whisper models (*.bin) in the models
+// directory, sorted by name.
+func (a *App) ListWhisperModels() ([]string, error) {
+	dir := whisperModelDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var models []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".bin") {
+			continue
+		}
+		models = append(models, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(models)
+	return models, nil
+}
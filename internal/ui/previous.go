@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"blackbox/internal/pathx"
+)
+
+// previousSummaryNote is prepended to summaries that built on an earlier one.
+const previousSummaryNote = "> Note: refined from the previous summary.\n\n"
+
+// previousSummary returns the summary from an earlier run, preferring the
+// separate summary file and falling back to one appended to the transcript.
+// Notes added by Summarise are stripped. Returns "" if there is none.
+func previousSummary(summaryPath, transcript string) string {
+	if b, err := os.ReadFile(pathx.Long(summaryPath)); err == nil {
+		if s := stripSummaryNotes(string(b)); s != "" {
+			return s
+		}
+	}
+	if i := strings.Index(transcript, transcriptSummaryDelimiter); i >= 0 {
+		return stripSummaryNotes(transcript[i+len(transcriptSummaryDelimiter):])
+	}
+	return ""
+}
+
+// stripSummaryNotes removes the leading "> Note:" lines Summarise adds.
+func stripSummaryNotes(summary string) string {
+	for {
+		summary = strings.TrimSpace(summary)
+		if !strings.HasPrefix(summary, "> Note:") {
+			return summary
+		}
+		i := strings.IndexByte(summary, '\n')
+		if i < 0 {
+			return ""
+		}
+		summary = summary[i+1:]
+	}
+}
+
+// withPreviousSummary inserts the previous summary as context between the
+// system prompt and the transcript. messages is returned unchanged if previous is empty.
+func withPreviousSummary(messages []chatMessage, previous string) []chatMessage {
+	if previous == "" || len(messages) == 0 {
+		return messages
+	}
+	prior := chatMessage{
+		Role:    "user",
+		Content: "Here is a previous summary of this transcript. Build on and refine it rather than starting from scratch:\n\n" + previous,
+	}
+	out := make([]chatMessage, 0, len(messages)+1)
+	out = append(out, messages[0], prior)
+	return append(out, messages[1:]...)
+}
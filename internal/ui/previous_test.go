@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStripSummaryNotes(t *testing.T) {
+	const body = "- Ship on Friday\n- Budget approved"
+	tests := []struct {
+		name, in, want string
+	}{
+		{"no notes", body, body},
+		{"previous", previousSummaryNote + body, body},
+		{"all notes", truncationNote(4000) + redactionNote(2) + previousSummaryNote + chunkedSummaryNote(3) + body, body},
+		{"quote in body kept", "Summary\n> Note: a quoted line", "Summary\n> Note: a quoted line"},
+		{"only notes", redactionNote(1), ""},
+		{"note without newline", "> Note: truncated", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripSummaryNotes(tt.in); got != tt.want {
+				t.Errorf("stripSummaryNotes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviousSummary(t *testing.T) {
+	dir := t.TempDir()
+	summaryPath := filepath.Join(dir, "call_summary.txt")
+	appended := "transcript text" + transcriptSummaryDelimiter + previousSummaryNote + "Appended summary\n"
+
+	if got := previousSummary(summaryPath, "transcript text"); got != "" {
+		t.Errorf("no previous summary: got %q", got)
+	}
+	if got := previousSummary(summaryPath, appended); got != "Appended summary" {
+		t.Errorf("appended only: got %q", got)
+	}
+	if err := os.WriteFile(summaryPath, []byte(redactionNote(1)+"File summary"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := previousSummary(summaryPath, appended); got != "File summary" {
+		t.Errorf("summary file: got %q, want it preferred over the appended one", got)
+	}
+}
+
+// TestSummariseIncludesPreviousSummary checks the earlier summary is sent
+// to the model, without its notes, only when include_previous_summary is on.
+func TestSummariseIncludesPreviousSummary(t *testing.T) {
+	for _, include := range []bool{true, false} {
+		t.Run(fmt.Sprintf("include=%v", include), func(t *testing.T) {
+			a := newTestApp(t, UISettings{IncludePreviousSummary: include})
+			requests := fakeRemoteLLM(t, "Refined summary.")
+			dir := a.settings.Get().transcriptDir()
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			txt := filepath.Join(dir, "call.txt")
+			if err := os.WriteFile(txt, []byte("We agreed to ship on Friday."), 0644); err != nil {
+				t.Fatal(err)
+			}
+			old := chunkedSummaryNote(2) + "Earlier summary: ship Friday."
+			if err := os.WriteFile(filepath.Join(dir, "call_summary.txt"), []byte(old), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := a.Summarise(txt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sent := requests()
+			if len(sent) != 1 {
+				t.Fatalf("%d requests, want 1", len(sent))
+			}
+			if got := strings.Contains(sent[0], "Earlier summary: ship Friday."); got != include {
+				t.Errorf("previous summary sent = %v", got)
+			}
+			if strings.Contains(sent[0], "summarised in 2 parts") {
+				t.Error("previous summary sent with its notes")
+			}
+			if got := strings.Contains(out, previousSummaryNote); got != include {
+				t.Errorf("refined note present = %v", got)
+			}
+		})
+	}
+}
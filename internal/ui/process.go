@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	wruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"blackbox/internal/audio"
+)
+
+// ListAudioProcesses returns running processes that can be chosen as the
+// loopback_process capture target.
+func (a *App) ListAudioProcesses() ([]audio.Process, error) {
+	return audio.ListProcesses()
+}
+
+// IsProcessLoopbackSupported reports whether this build can capture a single
+// application's audio (requires the processloopback build tag).
+func (a *App) IsProcessLoopbackSupported() bool {
+	return audio.ProcessLoopbackSupported
+}
+
+// ListRenderDevices returns playback devices that can be chosen as loopback_device.
+func (a *App) ListRenderDevices() ([]audio.DeviceInfo, error) {
+	return audio.ListRenderDevices()
+}
+
+// startLoopback starts r on the configured process when one is set, falling
+// back to device loopback if the process can't be found or captured. Device
+// loopback uses loopback_device, or the default render device when unset.
+func (a *App) startLoopback(r *audio.Recorder, cfg UISettings, sampleRate, channels uint32) error {
+	if target := strings.TrimSpace(cfg.LoopbackProcess); target != "" {
+		err := a.startProcessLoopback(r, target, sampleRate, channels)
+		if err == nil {
+			return nil
+		}
+		if a.uiCtx != nil {
+			wruntime.LogWarningf(a.uiCtx, "process loopback for %q unavailable, capturing all system audio: %v", target, err)
+		}
+	}
+
+	var deviceID string
+	if query := strings.TrimSpace(cfg.LoopbackDevice); query != "" {
+		devices, err := audio.ListRenderDevices()
+		if err != nil {
+			return err
+		}
+		device, err := audio.FindRenderDevice(devices, query)
+		if err != nil {
+			return fmt.Errorf("loopback_device: %w", err)
+		}
+		deviceID = device.ID
+	}
+	return r.StartOnDevice(deviceID, sampleRate, channels)
+}
+
+func (a *App) startProcessLoopback(r *audio.Recorder, target string, sampleRate, channels uint32) error {
+	if !audio.ProcessLoopbackSupported {
+		return audio.ErrProcessLoopbackUnsupported
+	}
+	procs, err := audio.ListProcesses()
+	if err != nil {
+		return err
+	}
+	proc, err := audio.FindProcess(procs, target)
+	if err != nil {
+		return err
+	}
+	return r.StartProcess(proc.PID, sampleRate, channels)
+}
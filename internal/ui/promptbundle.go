@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"blackbox/internal/pathx"
+)
+
+// promptBundleVersion is written to exported bundles for future format changes.
+const promptBundleVersion = 1
+
+// promptBundle is the file written by ExportPrompts.
+type promptBundle struct {
+	Version int            `json:"version"`
+	Prompts []PromptConfig `json:"prompts"`
+}
+
+// ExportPrompts writes every custom prompt to a single JSON bundle at destPath.
+// Built-in prompts aren't included.
+func (a *App) ExportPrompts(destPath string) error {
+	if strings.TrimSpace(destPath) == "" {
+		return errors.New("destination path required")
+	}
+	custom, err := loadCustomPrompts()
+	if err != nil {
+		return fmt.Errorf("failed to load custom prompts: %w", err)
+	}
+	bundle := promptBundle{Version: promptBundleVersion, Prompts: []PromptConfig{}}
+	for key, p := range custom {
+		if strings.TrimSpace(p.Prompt) == "" {
+			continue // other JSON in the config directory, e.g. ui.json
+		}
+		bundle.Prompts = append(bundle.Prompts, PromptConfig{Name: p.Name, Description: p.Description, Prompt: p.Prompt, Key: key})
+	}
+	sort.Slice(bundle.Prompts, func(i, j int) bool { return bundle.Prompts[i].Key < bundle.Prompts[j].Key })
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal prompt bundle: %w", err)
+	}
+	if err := os.MkdirAll(pathx.Long(filepath.Dir(destPath)), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(pathx.Long(destPath), data, 0644); err != nil {
+		return fmt.Errorf("failed to write prompt bundle: %w", err)
+	}
+	return nil
+}
+
+// ImportPrompts restores the prompts in a bundle written by ExportPrompts.
+// Prompts whose key matches an existing prompt (built-ins included) are
+// skipped unless overwrite is set, which never replaces a built-in. Returns
+// how many prompts were written.
+func (a *App) ImportPrompts(srcPath string, overwrite bool) (int, error) {
+	data, err := os.ReadFile(pathx.Long(srcPath))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read prompt bundle: %w", err)
+	}
+	var bundle promptBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return 0, fmt.Errorf("failed to parse prompt bundle: %w", err)
+	}
+	if bundle.Version > promptBundleVersion {
+		return 0, fmt.Errorf("prompt bundle version %d is newer than supported (%d)", bundle.Version, promptBundleVersion)
+	}
+
+	// Check every key before writing anything, so a bad bundle imports nothing
+	keys := make([]string, len(bundle.Prompts))
+	for i, p := range bundle.Prompts {
+		keys[i] = p.Key
+		if keys[i] == "" {
+			keys[i] = p.Name
+		}
+		if err := validatePromptKey(keys[i]); err != nil {
+			return 0, err
+		}
+	}
+	if err := os.MkdirAll("./config", 0755); err != nil {
+		return 0, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	custom, _ := loadCustomPrompts()
+
+	imported := 0
+	for i, p := range bundle.Prompts {
+		key := keys[i]
+		if strings.TrimSpace(p.Prompt) == "" {
+			continue
+		}
+		_, exists := custom[key]
+		if isBuiltinPrompt(key) || (exists && !overwrite) {
+			continue
+		}
+
+		fileConfig := PromptConfig{Name: p.Name, Description: p.Description, Prompt: p.Prompt}
+		if fileConfig.Name == "" {
+			fileConfig.Name = key
+		}
+		out, err := json.MarshalIndent(fileConfig, "", "  ")
+		if err != nil {
+			return imported, fmt.Errorf("failed to marshal prompt config: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join("./config", key+".json"), out, 0644); err != nil {
+			return imported, fmt.Errorf("failed to write prompt file: %w", err)
+		}
+
+		fileConfig.Key = key
+		a.promptMu.Lock()
+		a.promptCache[key] = fileConfig
+		a.promptMu.Unlock()
+		imported++
+	}
+	return imported, nil
+}
+
+// windowsReservedNames can't be used as file names on Windows, with or
+// without an extension.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// validatePromptKey checks that key is safe to use as a file name in the
+// config directory (it is saved as <key>.json) and doesn't clash with ui.json.
+func validatePromptKey(key string) error {
+	stem, _, _ := strings.Cut(key, ".")
+	switch {
+	case strings.TrimSpace(key) == "",
+		key != filepath.Base(key),
+		strings.ContainsAny(key, `/\:*?"<>|`),
+		strings.IndexFunc(key, unicode.IsControl) >= 0,
+		strings.HasSuffix(key, ".") || strings.HasSuffix(key, " "),
+		strings.EqualFold(key, "ui"),
+		windowsReservedNames[strings.ToUpper(strings.TrimSpace(stem))]:
+		return fmt.Errorf("invalid prompt key %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPromptBundleRoundTrip(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	prompts := []PromptConfig{
+		{Name: "standup", Description: "Daily standup", Prompt: "List blockers."},
+		{Name: "retro", Prompt: "What went well?"},
+	}
+	for _, p := range prompts {
+		if err := a.SaveCustomPrompt(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bundlePath := filepath.Join(t.TempDir(), "prompts.json")
+	if err := a.ExportPrompts(bundlePath); err != nil {
+		t.Fatal(err)
+	}
+
+	// Import into a fresh config directory
+	b := newTestApp(t, UISettings{})
+	n, err := b.ImportPrompts(bundlePath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(prompts) {
+		t.Fatalf("imported %d prompts, want %d", n, len(prompts))
+	}
+	for _, want := range prompts {
+		got, err := b.GetPromptConfig(want.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Name != want.Name || got.Description != want.Description || got.Prompt != want.Prompt {
+			t.Errorf("imported %+v, want %+v", got, want)
+		}
+	}
+
+	// Existing prompts are kept unless overwrite is set
+	if n, err := b.ImportPrompts(bundlePath, false); err != nil || n != 0 {
+		t.Errorf("re-import wrote %d prompts (err %v), want 0", n, err)
+	}
+	if n, err := b.ImportPrompts(bundlePath, true); err != nil || n != len(prompts) {
+		t.Errorf("overwrite import wrote %d prompts (err %v), want %d", n, err, len(prompts))
+	}
+}
+
+func TestImportPromptsRejectsBadKeys(t *testing.T) {
+	for _, key := range []string{"", "..", "../evil", `a\b`, "c:x", "UI", "ui", "con", "CON", "nul.backup", "Com1", "lpt9", "trailing.", "what?"} {
+		t.Run(key, func(t *testing.T) {
+			a := newTestApp(t, UISettings{})
+			data, _ := json.Marshal(promptBundle{Version: promptBundleVersion, Prompts: []PromptConfig{
+				{Key: "good", Prompt: "fine"},
+				{Key: key, Prompt: "bad"},
+			}})
+			bundlePath := filepath.Join(t.TempDir(), "prompts.json")
+			if err := os.WriteFile(bundlePath, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := a.ImportPrompts(bundlePath, true); err == nil {
+				t.Fatalf("key %q accepted", key)
+			}
+			if _, err := os.Stat(filepath.Join("config", "good.json")); !os.IsNotExist(err) {
+				t.Errorf("valid prompt was written before the bad key was rejected")
+			}
+		})
+	}
+}
+
+func TestValidatePromptKey(t *testing.T) {
+	for _, key := range []string{"standup", "team-retro", "1on1", "notes.v2", "console", "com10", "auxiliary"} {
+		if err := validatePromptKey(key); err != nil {
+			t.Errorf("validatePromptKey(%q) = %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Provider summarises text with one LLM API.
+type Provider interface {
+	Summarise(ctx context.Context, system, user string) (string, error)
+}
+
+// streamingProvider is implemented by providers that can stream their reply.
+type streamingProvider interface {
+	SummariseStream(ctx context.Context, system, user string, onDelta func(string)) (string, error)
+}
+
+// newProvider returns the Provider for cfg's provider field ("openai" and
+// "azure" share the chat completions schema).
+func newProvider(cfg *llmConfig, maxTokens int) Provider {
+	if cfg.isAnthropic() {
+		return &AnthropicProvider{cfg: cfg, maxTokens: maxTokens}
+	}
+	return &OpenAIProvider{cfg: cfg, maxTokens: maxTokens}
+}
+
+// providerComplete adapts p to a completeFunc, streaming when p supports it
+// and a delta callback is given. Messages are flattened to one system and one
+// user turn. Requests are made with ctx.
+func providerComplete(ctx context.Context, p Provider) completeFunc {
+	return func(messages []chatMessage, onDelta func(string)) (string, error) {
+		system, user := flattenMessages(messages)
+		if sp, ok := p.(streamingProvider); ok && onDelta != nil {
+			return sp.SummariseStream(ctx, system, user, onDelta)
+		}
+		return p.Summarise(ctx, system, user)
+	}
+}
+
+// flattenMessages joins system messages and the remaining user messages.
+func flattenMessages(messages []chatMessage) (system, user string) {
+	var sys, usr []string
+	for _, m := range messages {
+		if m.Role == "system" {
+			sys = append(sys, m.Content)
+		} else {
+			usr = append(usr, m.Content)
+		}
+	}
+	return strings.Join(sys, "\n\n"), strings.Join(usr, "\n\n")
+}
+
+// OpenAIProvider uses the OpenAI chat completions API (also Azure OpenAI and
+// llama-server).
+type OpenAIProvider struct {
+	cfg       *llmConfig
+	maxTokens int
+}
+
+func (p *OpenAIProvider) request(system, user string) chatRequest {
+	var messages []chatMessage
+	if system != "" {
+		messages = append(messages, chatMessage{Role: "system", Content: system})
+	}
+	messages = append(messages, chatMessage{Role: "user", Content: user})
+	return chatRequest{Model: p.cfg.Model, Messages: messages, MaxTokens: p.maxTokens}
+}
+
+// Summarise sends a single chat completion request, retrying transient failures.
+func (p *OpenAIProvider) Summarise(ctx context.Context, system, user string) (string, error) {
+	return withRetries(ctx, p.cfg.maxRetries(), func() (string, bool, error) {
+		out, err := makeOpenAIRequest(ctx, p.cfg, p.request(system, user))
+		return out, false, err
+	})
+}
+
+// SummariseStream streams the completion to onDelta. Failures are retried
+// only until the first delta has been delivered.
+func (p *OpenAIProvider) SummariseStream(ctx context.Context, system, user string, onDelta func(string)) (string, error) {
+	return withRetries(ctx, p.cfg.maxRetries(), func() (string, bool, error) {
+		delivered := false
+		out, err := makeOpenAIRequestStream(ctx, p.cfg, p.request(system, user), func(d string) {
+			delivered = true
+			onDelta(d)
+		})
+		return out, delivered, err
+	})
+}
+
+// anthropicVersion is the Messages API version sent with each request.
+const anthropicVersion = "2023-06-01"
+
+// AnthropicProvider uses the Anthropic Messages API (/v1/messages).
+type AnthropicProvider struct {
+	cfg       *llmConfig
+	maxTokens int
+}
+
+type anthropicRequest struct {
+	Model     string        `json:"model"`
+	MaxTokens int           `json:"max_tokens"`
+	System    string        `json:"system,omitempty"`
+	Messages  []chatMessage `json:"messages"`
+	Stream    bool          `json:"stream,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// anthropicStreamEvent is one SSE data payload of a streamed Messages reply.
+// Only text deltas, the end of the message and errors are used.
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Summarise sends a Messages API request, retrying transient failures.
+func (p *AnthropicProvider) Summarise(ctx context.Context, system, user string) (string, error) {
+	return withRetries(ctx, p.cfg.maxRetries(), func() (string, bool, error) {
+		out, err := p.send(ctx, system, user)
+		return out, false, err
+	})
+}
+
+// SummariseStream streams the reply to onDelta. Failures are retried only
+// until the first delta has been delivered.
+func (p *AnthropicProvider) SummariseStream(ctx context.Context, system, user string, onDelta func(string)) (string, error) {
+	return withRetries(ctx, p.cfg.maxRetries(), func() (string, bool, error) {
+		delivered := false
+		out, err := p.sendStream(ctx, system, user, func(d string) {
+			delivered = true
+			onDelta(d)
+		})
+		return out, delivered, err
+	})
+}
+
+// newRequest builds a Messages API request for system and user.
+func (p *AnthropicProvider) newRequest(ctx context.Context, system, user string, stream bool) (*http.Request, error) {
+	jsonData, err := json.Marshal(anthropicRequest{
+		Model:     p.cfg.Model,
+		MaxTokens: p.maxTokens,
+		System:    system,
+		Messages:  []chatMessage{{Role: "user", Content: user}},
+		Stream:    stream,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", p.cfg.messagesURL(), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.cfg.APIKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+	if stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+	return req, nil
+}
+
+func (p *AnthropicProvider) send(ctx context.Context, system, user string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 360*time.Second)
+	defer cancel()
+	req, err := p.newRequest(ctx, system, user, false)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", newAPIStatusError(resp, body)
+	}
+	return parseAnthropicResponse(body)
+}
+
+// sendStream is send with "stream": true, calling onDelta with each piece of
+// text as it arrives. Like makeOpenAIRequestStream it has no overall time
+// limit, only streamIdleTimeout between events.
+func (p *AnthropicProvider) sendStream(ctx context.Context, system, user string, onDelta func(string)) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	req, err := p.newRequest(ctx, system, user, true)
+	if err != nil {
+		return "", err
+	}
+
+	idle := time.AfterFunc(streamIdleTimeout, cancel)
+	defer idle.Stop()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", newAPIStatusError(resp, body)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/event-stream" {
+		// Proxy or server ignored "stream"; treat it as a normal reply
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("failed to read response: %w", err)
+		}
+		return parseAnthropicResponse(body)
+	}
+
+	// Each event is an "event:" line and a "data:" line whose JSON repeats
+	// the type, so only the data lines are read
+	var sb strings.Builder
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		idle.Reset(streamIdleTimeout)
+		line = bytes.TrimSpace(line)
+		if data, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+			var event anthropicStreamEvent
+			if err := json.Unmarshal(bytes.TrimSpace(data), &event); err != nil {
+				return "", fmt.Errorf("failed to parse stream event: %w", err)
+			}
+			switch event.Type {
+			case "content_block_delta":
+				if event.Delta.Type == "text_delta" && event.Delta.Text != "" {
+					sb.WriteString(event.Delta.Text)
+					if onDelta != nil {
+						onDelta(event.Delta.Text)
+					}
+				}
+			case "error":
+				if event.Error != nil {
+					return "", fmt.Errorf("API error: %s", event.Error.Message)
+				}
+				return "", fmt.Errorf("API error in stream")
+			}
+			if event.Type == "message_stop" {
+				break
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			if ctx.Err() != nil {
+				return "", fmt.Errorf("stream stalled for %s", streamIdleTimeout)
+			}
+			return "", fmt.Errorf("failed to read stream: %w", readErr)
+		}
+	}
+	if sb.Len() == 0 {
+		return "", fmt.Errorf("no text content in streamed response")
+	}
+	return sb.String(), nil
+}
+
+// parseAnthropicResponse joins the text blocks of a Messages API reply.
+func parseAnthropicResponse(body []byte) (string, error) {
+	var msgResp anthropicResponse
+	if err := json.Unmarshal(body, &msgResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if msgResp.Error != nil {
+		return "", fmt.Errorf("API error: %s", msgResp.Error.Message)
+	}
+	var sb strings.Builder
+	for _, block := range msgResp.Content {
+		if block.Type == "text" {
+			sb.WriteString(block.Text)
+		}
+	}
+	if sb.Len() == 0 {
+		return "", fmt.Errorf("no text content in API response")
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// capturedRequest is what a fake API server received.
+type capturedRequest struct {
+	path, query string
+	header      http.Header
+	body        map[string]any
+}
+
+// fakeAPI serves reply (with contentType) to every request and records them.
+func fakeAPI(t *testing.T, contentType, reply string) (*httptest.Server, *[]capturedRequest) {
+	t.Helper()
+	var got []capturedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		if err := json.Unmarshal(b, &body); err != nil {
+			t.Errorf("request body is not JSON: %s", b)
+		}
+		got = append(got, capturedRequest{r.URL.Path, r.URL.RawQuery, r.Header.Clone(), body})
+		w.Header().Set("Content-Type", contentType)
+		io.WriteString(w, reply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &got
+}
+
+func TestOpenAIProviderRequest(t *testing.T) {
+	const reply = `{"choices":[{"message":{"content":"the summary"}}]}`
+	tests := []struct {
+		name       string
+		cfg        llmConfig
+		path       string
+		query      string
+		authHeader string
+		authValue  string
+	}{
+		{"openai", llmConfig{APIKey: "sk-1", Model: "gpt-test"}, "/chat/completions", "", "Authorization", "Bearer sk-1"},
+		{"azure", llmConfig{Provider: "azure", APIKey: "az-1", AzureDeployment: "dep", AzureAPIVersion: "2024-10-21"},
+			"/openai/deployments/dep/chat/completions", "api-version=2024-10-21", "api-key", "az-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, got := fakeAPI(t, "application/json", reply)
+			cfg := tt.cfg
+			cfg.BaseURL = srv.URL
+			out, err := newProvider(&cfg, 123).Summarise(context.Background(), "be brief", "transcript text")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out != "the summary" {
+				t.Errorf("got %q", out)
+			}
+			r := (*got)[0]
+			if r.path != tt.path || r.query != tt.query {
+				t.Errorf("request to %s?%s, want %s?%s", r.path, r.query, tt.path, tt.query)
+			}
+			if v := r.header.Get(tt.authHeader); v != tt.authValue {
+				t.Errorf("%s = %q, want %q", tt.authHeader, v, tt.authValue)
+			}
+			if tt.authHeader != "Authorization" && r.header.Get("Authorization") != "" {
+				t.Error("Authorization header sent alongside api-key")
+			}
+			want := `[{"content":"be brief","role":"system"},{"content":"transcript text","role":"user"}]`
+			if msgs, _ := json.Marshal(r.body["messages"]); string(msgs) != want {
+				t.Errorf("messages = %s, want %s", msgs, want)
+			}
+			if r.body["max_completion_tokens"] != float64(123) {
+				t.Errorf("max_completion_tokens = %v", r.body["max_completion_tokens"])
+			}
+		})
+	}
+}
+
+func TestOpenAIProviderStream(t *testing.T) {
+	stream := "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n" +
+		"data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n\n" +
+		"data: [DONE]\n\n"
+	srv, got := fakeAPI(t, "text/event-stream", stream)
+	p := newProvider(&llmConfig{BaseURL: srv.URL, APIKey: "k", Model: "m"}, 10).(streamingProvider)
+	var deltas []string
+	out, err := p.SummariseStream(context.Background(), "", "hi", func(d string) { deltas = append(deltas, d) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "Hello" || strings.Join(deltas, "|") != "Hel|lo" {
+		t.Errorf("got %q from deltas %q", out, deltas)
+	}
+	r := (*got)[0]
+	if r.body["stream"] != true || r.header.Get("Accept") != "text/event-stream" {
+		t.Errorf("not a streaming request: %v %v", r.body, r.header)
+	}
+	if msgs, _ := json.Marshal(r.body["messages"]); string(msgs) != `[{"content":"hi","role":"user"}]` {
+		t.Errorf("empty system prompt sent: %s", msgs)
+	}
+}
+
+func TestAnthropicProviderRequest(t *testing.T) {
+	srv, got := fakeAPI(t, "application/json", `{"content":[{"type":"text","text":"the "},{"type":"text","text":"summary"}]}`)
+	for _, base := range []string{srv.URL, srv.URL + "/v1", srv.URL + "/v1/"} {
+		cfg := &llmConfig{Provider: "anthropic", BaseURL: base, APIKey: "ak-1", Model: "claude-test"}
+		out, err := newProvider(cfg, 456).Summarise(context.Background(), "be brief", "transcript text")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != "the summary" {
+			t.Errorf("got %q", out)
+		}
+	}
+	for _, r := range *got {
+		if r.path != "/v1/messages" {
+			t.Errorf("request to %s", r.path)
+		}
+		if r.header.Get("x-api-key") != "ak-1" || r.header.Get("anthropic-version") != anthropicVersion {
+			t.Errorf("headers = %v", r.header)
+		}
+		if r.header.Get("Authorization") != "" {
+			t.Error("Authorization header sent to Anthropic")
+		}
+		if r.body["system"] != "be brief" || r.body["model"] != "claude-test" || r.body["max_tokens"] != float64(456) {
+			t.Errorf("body = %v", r.body)
+		}
+		if msgs, _ := json.Marshal(r.body["messages"]); string(msgs) != `[{"content":"transcript text","role":"user"}]` {
+			t.Errorf("messages = %s", msgs)
+		}
+		if _, ok := r.body["stream"]; ok {
+			t.Error("non-streaming request has stream set")
+		}
+	}
+}
+
+func TestAnthropicProviderStream(t *testing.T) {
+	events := []struct{ name, data string }{
+		{"message_start", `{"type":"message_start","message":{"id":"msg_1","content":[]}}`},
+		{"content_block_start", `{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`},
+		{"ping", `{"type":"ping"}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"lo"}}`},
+		{"content_block_stop", `{"type":"content_block_stop","index":0}`},
+		{"message_delta", `{"type":"message_delta","delta":{"stop_reason":"end_turn"}}`},
+		{"message_stop", `{"type":"message_stop"}`},
+	}
+	var sb strings.Builder
+	for _, e := range events {
+		fmt.Fprintf(&sb, "event: %s\ndata: %s\n\n", e.name, e.data)
+	}
+	srv, got := fakeAPI(t, "text/event-stream; charset=utf-8", sb.String())
+
+	p, ok := newProvider(&llmConfig{Provider: "anthropic", BaseURL: srv.URL, APIKey: "k", Model: "m"}, 10).(streamingProvider)
+	if !ok {
+		t.Fatal("AnthropicProvider doesn't stream")
+	}
+	var deltas []string
+	out, err := p.SummariseStream(context.Background(), "sys", "hi", func(d string) { deltas = append(deltas, d) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "Hello" || strings.Join(deltas, "|") != "Hel|lo" {
+		t.Errorf("got %q from deltas %q", out, deltas)
+	}
+	r := (*got)[0]
+	if r.body["stream"] != true || r.header.Get("Accept") != "text/event-stream" || r.header.Get("x-api-key") != "k" {
+		t.Errorf("not a streaming Messages request: %v %v", r.body, r.header)
+	}
+}
+
+func TestAnthropicProviderStreamErrors(t *testing.T) {
+	tests := []struct {
+		name, contentType, body, want, wantErr string
+	}{
+		{"error event", "text/event-stream", "event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n", "", "Overloaded"},
+		{"no text", "text/event-stream", "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n", "", "no text content"},
+		{"plain reply", "application/json", `{"content":[{"type":"text","text":"whole"}]}`, "whole", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := fakeAPI(t, tt.contentType, tt.body)
+			p := &AnthropicProvider{cfg: &llmConfig{Provider: "anthropic", BaseURL: srv.URL, APIKey: "k", Model: "m", MaxRetries: -1}, maxTokens: 10}
+			out, err := p.SummariseStream(context.Background(), "", "hi", func(string) {})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || out != tt.want {
+				t.Errorf("got %q, %v; want %q", out, err, tt.want)
+			}
+		})
+	}
+}
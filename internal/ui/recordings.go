@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"blackbox/internal/execx"
+	"blackbox/internal/wav"
+)
+
+// RecordingInfo describes a recording found in the output directory.
+type RecordingInfo struct {
+	Path            string    `json:"path"`
+	Name            string    `json:"name"`
+	SampleRate      int       `json:"sample_rate"`
+	Channels        int       `json:"channels"`
+	BitsPerSample   int       `json:"bits_per_sample"` // 0 for Opus, which has no fixed sample size
+	DurationSeconds float64   `json:"duration_seconds"`
+	FileSize        int64     `json:"file_size"`
+	ModifiedAt      time.Time `json:"modified_at"`
+}
+
+// recordingExts are the file extensions written for each AudioFormat.
+var recordingExts = []string{".wav", ".ogg", ".flac"}
+
+func isRecordingFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return slices.Contains(recordingExts, ext)
+}
+
+// listRecordings scans OutDir for recordings in any output format. WAV
+// formats come from the header; Ogg/Opus and FLAC are probed with ffmpeg and
+// are still listed, with an unknown format, if that fails. WAVs that can't be
+// parsed are skipped. Results are ordered newest first.
+func (a *App) listRecordings() ([]RecordingInfo, error) {
+	outDir := a.settings.Get().OutDir
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var recs []RecordingInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !isRecordingFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		rec := RecordingInfo{
+			Path:       filepath.Join(outDir, entry.Name()),
+			Name:       entry.Name(),
+			FileSize:   info.Size(),
+			ModifiedAt: info.ModTime(),
+		}
+		if strings.EqualFold(filepath.Ext(entry.Name()), ".wav") {
+			r, err := wav.OpenReader(rec.Path)
+			if err != nil {
+				continue // Skip files that aren't valid WAVs
+			}
+			rec.SampleRate = int(r.SampleRate())
+			rec.Channels = int(r.Channels())
+			rec.BitsPerSample = int(r.BitsPerSample())
+			rec.DurationSeconds = r.Duration().Seconds()
+			r.Close()
+		} else if probe, err := execx.ProbeAudio(ffmpegBin(), rec.Path); err == nil {
+			rec.SampleRate = probe.SampleRate
+			rec.Channels = probe.Channels
+			rec.BitsPerSample = probe.BitsPerSample
+			rec.DurationSeconds = probe.Duration.Seconds()
+		}
+		recs = append(recs, rec)
+	}
+
+	sort.Slice(recs, func(i, j int) bool { return recs[i].ModifiedAt.After(recs[j].ModifiedAt) })
+	return recs, nil
+}
+
+// ListRecordingsByFormat returns recordings matching the given format.
+// Nil filters match any value.
+func (a *App) ListRecordingsByFormat(sampleRate, channels, bits *int) ([]RecordingInfo, error) {
+	recs, err := a.listRecordings()
+	if err != nil {
+		return nil, err
+	}
+	matched := []RecordingInfo{}
+	for _, r := range recs {
+		if sampleRate != nil && r.SampleRate != *sampleRate {
+			continue
+		}
+		if channels != nil && r.Channels != *channels {
+			continue
+		}
+		if bits != nil && r.BitsPerSample != *bits {
+			continue
+		}
+		matched = append(matched, r)
+	}
+	return matched, nil
+}
+
+// ListRecordingsByDuration returns recordings whose length lies within
+// [minSec, maxSec], newest first. Nil bounds are open. Recordings with no
+// known duration (e.g. an unfinalised header) are included only when
+// includeUnknown is set. limit <= 0 returns all after offset.
+func (a *App) ListRecordingsByDuration(minSec, maxSec *float64, includeUnknown bool, limit, offset int) ([]RecordingInfo, error) {
+	recs, err := a.listRecordings()
+	if err != nil {
+		return nil, err
+	}
+	matched := []RecordingInfo{}
+	for _, r := range recs {
+		if r.DurationSeconds <= 0 {
+			if includeUnknown {
+				matched = append(matched, r)
+			}
+			continue
+		}
+		if minSec != nil && r.DurationSeconds < *minSec {
+			continue
+		}
+		if maxSec != nil && r.DurationSeconds > *maxSec {
+			continue
+		}
+		matched = append(matched, r)
+	}
+	if offset >= len(matched) {
+		return []RecordingInfo{}, nil
+	}
+	matched = matched[max(offset, 0):]
+	if limit > 0 && limit < len(matched) {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestListRecordingsByFormat(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	out := a.settings.Get().OutDir
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	seed := []struct {
+		name     string
+		rate, ch int
+	}{
+		{"meeting_16k.wav", 16000, 1},
+		{"call_48k_stereo.wav", 48000, 2},
+		{"call_48k_mono.wav", 48000, 1},
+		{"podcast_44k.wav", 44100, 2},
+	}
+	for _, s := range seed {
+		writeTestWAV(t, filepath.Join(out, s.name), s.rate, s.ch, sine(s.rate, s.ch, 0.1, 0.5))
+	}
+	// Not a WAV, so it's skipped rather than failing the listing
+	if err := os.WriteFile(filepath.Join(out, "broken.wav"), []byte("not audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ptr := func(v int) *int { return &v }
+	tests := []struct {
+		name           string
+		rate, ch, bits *int
+		want           []string
+	}{
+		{"all", nil, nil, nil, []string{"call_48k_mono.wav", "call_48k_stereo.wav", "meeting_16k.wav", "podcast_44k.wav"}},
+		{"48 kHz", ptr(48000), nil, nil, []string{"call_48k_mono.wav", "call_48k_stereo.wav"}},
+		{"16 kHz", ptr(16000), nil, nil, []string{"meeting_16k.wav"}},
+		{"48 kHz stereo", ptr(48000), ptr(2), nil, []string{"call_48k_stereo.wav"}},
+		{"16-bit", nil, nil, ptr(16), []string{"call_48k_mono.wav", "call_48k_stereo.wav", "meeting_16k.wav", "podcast_44k.wav"}},
+		{"no match", ptr(8000), nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs, err := a.ListRecordingsByFormat(tt.rate, tt.ch, tt.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range recs {
+				got = append(got, r.Name)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if recs == nil {
+				t.Error("got nil, want an empty slice")
+			}
+		})
+	}
+}
+
+func TestListRecordingsByDuration(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	t.Setenv("LOOPBACK_NOTES_FFMPEG_BIN", filepath.Join(t.TempDir(), "missing-ffmpeg"))
+	out := a.settings.Get().OutDir
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Oldest first, so listing order is the reverse of this
+	seed := []struct {
+		name string
+		secs float64
+	}{
+		{"short.wav", 1},
+		{"edge.wav", 2},
+		{"medium.wav", 3},
+		{"long.wav", 5},
+	}
+	base := time.Now().Add(-time.Hour)
+	for i, s := range seed {
+		path := filepath.Join(out, s.name)
+		writeTestWAV(t, path, 8000, 1, sine(8000, 1, s.secs, 0.5))
+		mtime := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Unprobeable without ffmpeg, so its duration is unknown; it's the newest
+	if err := os.WriteFile(filepath.Join(out, "pending.ogg"), []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ptr := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name          string
+		min, max      *float64
+		unknown       bool
+		limit, offset int
+		want          []string
+	}{
+		{"all", nil, nil, false, 0, 0, []string{"long.wav", "medium.wav", "edge.wav", "short.wav"}},
+		{"include unknown", nil, nil, true, 0, 0, []string{"pending.ogg", "long.wav", "medium.wav", "edge.wav", "short.wav"}},
+		{"bounds inclusive", ptr(2), ptr(3), false, 0, 0, []string{"medium.wav", "edge.wav"}},
+		{"min only", ptr(3), nil, false, 0, 0, []string{"long.wav", "medium.wav"}},
+		{"max only", nil, ptr(2), false, 0, 0, []string{"edge.wav", "short.wav"}},
+		{"limit", nil, nil, false, 2, 0, []string{"long.wav", "medium.wav"}},
+		{"limit and offset", nil, nil, false, 2, 1, []string{"medium.wav", "edge.wav"}},
+		{"negative offset", nil, nil, false, 1, -3, []string{"long.wav"}},
+		{"offset past end", nil, nil, false, 0, 4, nil},
+		{"no match", ptr(10), nil, false, 0, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs, err := a.ListRecordingsByDuration(tt.min, tt.max, tt.unknown, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range recs {
+				got = append(got, r.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if recs == nil {
+				t.Error("got nil, want an empty slice")
+			}
+		})
+	}
+}
+
+// TestListRecordingsCompressed checks Ogg and FLAC recordings are listed even
+// when ffmpeg can't probe them, with their format left unknown.
+func TestListRecordingsCompressed(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	t.Setenv("LOOPBACK_NOTES_FFMPEG_BIN", filepath.Join(t.TempDir(), "missing-ffmpeg"))
+	out := a.settings.Get().OutDir
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestWAV(t, filepath.Join(out, "meeting.wav"), 16000, 1, sine(16000, 1, 0.1, 0.5))
+	for _, name := range []string{"call.ogg", "lecture.FLAC", "notes.txt", "cover.png"} {
+		if err := os.WriteFile(filepath.Join(out, name), []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recs, err := a.listRecordings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]RecordingInfo{}
+	for _, r := range recs {
+		got[r.Name] = r
+	}
+	if len(got) != 3 {
+		t.Fatalf("listed %v, want meeting.wav, call.ogg and lecture.FLAC", slices.Collect(maps.Keys(got)))
+	}
+	if got["meeting.wav"].SampleRate != 16000 {
+		t.Errorf("meeting.wav rate = %d, want 16000", got["meeting.wav"].SampleRate)
+	}
+	for _, name := range []string{"call.ogg", "lecture.FLAC"} {
+		if r, ok := got[name]; !ok || r.SampleRate != 0 || r.DurationSeconds != 0 || r.FileSize != 4 {
+			t.Errorf("%s = %+v, want listed with unknown format", name, r)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// redactionRule replaces matches of pattern with label.
+type redactionRule struct {
+	pattern *regexp.Regexp
+	label   string
+}
+
+// Built-in rules applied whenever redaction is enabled
+var defaultRedactionRules = []redactionRule{
+	{regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`), "[email]"},
+	// Phone numbers need a recognisable shape (leading +, bracketed area code
+	// or -/. separators) so amounts, IDs and lists of years are left alone
+	{regexp.MustCompile(`\+\d{1,3}[\s.-]?(?:\(\d{1,4}\)|\d{1,4})[\s.-]?\d{3,4}[\s.-]?\d{3,4}\b`), "[phone]"},
+	{regexp.MustCompile(`\(\d{2,4}\)[\s.-]?\d{3,4}[\s.-]?\d{3,4}\b`), "[phone]"},
+	{regexp.MustCompile(`\b\d{2,4}[.-]\d{3,4}[.-]\d{4}\b`), "[phone]"},
+}
+
+// redactTranscript replaces emails, phone numbers and any extra patterns in
+// text. Returns the redacted text and the number of replacements made.
+func redactTranscript(text string, extra []string) (string, int, error) {
+	rules := append([]redactionRule(nil), defaultRedactionRules...)
+	for _, p := range extra {
+		if p == "" {
+			continue
+		}
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		rules = append(rules, redactionRule{re, "[redacted]"})
+	}
+
+	count := 0
+	for _, r := range rules {
+		text = r.pattern.ReplaceAllStringFunc(text, func(string) string {
+			count++
+			return r.label
+		})
+	}
+	return text, count, nil
+}
+
+// redactionNote is prepended to summaries made from a redacted transcript.
+func redactionNote(count int) string {
+	return fmt.Sprintf("> Note: %d sensitive item(s) were redacted before sending to the remote API.\n\n", count)
+}
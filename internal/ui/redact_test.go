@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedactTranscript(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"mail jane.doe@example.com today", "mail [email] today"},
+		{"call +61 2 9876 5432 now", "call [phone] now"},
+		{"call +1 (555) 123-4567", "call [phone]"},
+		{"office (02) 9876 5432", "office [phone]"},
+		{"dial 555-123-4567.", "dial [phone]."},
+		{"dial 555.123.4567", "dial [phone]"},
+
+		// Not phone numbers
+		{"revenue was 12500000 dollars", "revenue was 12500000 dollars"},
+		{"see ticket 12345678", "see ticket 12345678"},
+		{"in 2023 2024 2025 we grew", "in 2023 2024 2025 we grew"},
+		{"on 2024-01-15 at 10:30", "on 2024-01-15 at 10:30"},
+		{"version 1.2.3", "version 1.2.3"},
+	}
+	for _, tt := range tests {
+		got, _, err := redactTranscript(tt.in, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("redactTranscript(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRedactTranscriptExtraPatterns(t *testing.T) {
+	got, n, err := redactTranscript("Project Falcon ships to a@b.io", []string{`(?i)project \w+`, ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "[redacted] ships to [email]" || n != 2 {
+		t.Errorf("got %q (%d replacements)", got, n)
+	}
+	if _, _, err := redactTranscript("x", []string{"("}); err == nil {
+		t.Error("invalid pattern accepted")
+	}
+}
+
+// TestSummariseRedactsOutgoingRequest checks that nothing sensitive reaches
+// the remote API when redact_remote is on.
+func TestSummariseRedactsOutgoingRequest(t *testing.T) {
+	var sent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = string(body)
+		w.Write([]byte(`{"choices":[{"message":{"content":"summary"}}]}`))
+	}))
+	defer srv.Close()
+
+	a := newTestApp(t, UISettings{RedactRemote: true, RedactPatterns: []string{`Falcon`}})
+	cfg, _ := json.Marshal(llmConfig{BaseURL: srv.URL, APIKey: "k", Model: "m"})
+	if err := os.MkdirAll("configs", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("configs", "remote.json"), cfg, 0644); err != nil {
+		t.Fatal(err)
+	}
+	txt := filepath.Join(t.TempDir(), "meeting.txt")
+	transcript := "Email jane@example.com or call +61 2 9876 5432 about Falcon. Budget 12500000."
+	if err := os.WriteFile(txt, []byte(transcript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := a.Summarise(txt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"jane@example.com", "9876 5432", "Falcon"} {
+		if strings.Contains(sent, leak) {
+			t.Errorf("request body contains %q: %s", leak, sent)
+		}
+	}
+	if !strings.Contains(sent, "12500000") {
+		t.Errorf("request body lost a plain number: %s", sent)
+	}
+	if !strings.Contains(out, redactionNote(3)) {
+		t.Errorf("summary missing redaction note: %s", out)
+	}
+}
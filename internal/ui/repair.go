@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"blackbox/internal/execx"
+	"blackbox/internal/pathx"
+	"blackbox/internal/wav"
+)
+
+// Repair outcomes reported in RepairResult.Status
+const (
+	RepairStatusOK            = "ok"            // file was already readable and consistent
+	RepairStatusRepaired      = "repaired"      // header sizes fixed in place
+	RepairStatusReencoded     = "reencoded"     // re-encoded by ffmpeg into a new file
+	RepairStatusUnrecoverable = "unrecoverable" // neither approach produced a readable WAV
+)
+
+// RepairResult describes what RepairRecording did.
+type RepairResult struct {
+	Status string `json:"status"`
+	Path   string `json:"path"` // the readable recording (new file when re-encoded)
+	Detail string `json:"detail,omitempty"`
+}
+
+// RepairRecording tries to make a WAV that fails to open readable again. It
+// first fixes the header sizes in place; if the header is beyond repair and
+// ffmpeg is available, it re-encodes whatever ffmpeg can decode into
+// <base>_repaired.wav at the original sample rate and channel count, leaving
+// the original untouched.
+func (a *App) RepairRecording(path string) (RepairResult, error) {
+	if strings.TrimSpace(path) == "" {
+		return RepairResult{}, errors.New("recording path required")
+	}
+	if _, err := os.Stat(pathx.Long(path)); err != nil {
+		return RepairResult{}, err
+	}
+
+	changed, headerErr := wav.RepairHeader(path)
+	if changed {
+		a.audioCache.invalidate(path)
+	}
+	if headerErr == nil {
+		if _, err := wav.Duration(path); err == nil {
+			if changed {
+				return RepairResult{Status: RepairStatusRepaired, Path: path}, nil
+			}
+			return RepairResult{Status: RepairStatusOK, Path: path}, nil
+		}
+	}
+
+	ffmpeg := ffmpegBin()
+	if _, err := os.Stat(ffmpeg); err != nil {
+		return RepairResult{
+			Status: RepairStatusUnrecoverable,
+			Path:   path,
+			Detail: fmt.Sprintf("header repair failed (%v) and ffmpeg is not available to re-encode", headerErr),
+		}, nil
+	}
+	outPath := strings.TrimSuffix(path, filepath.Ext(path)) + "_repaired.wav"
+	if err := execx.ReencodeWAV(ffmpeg, path, outPath); err != nil {
+		_ = os.Remove(outPath)
+		return RepairResult{
+			Status: RepairStatusUnrecoverable,
+			Path:   path,
+			Detail: fmt.Sprintf("header repair failed (%v); re-encode failed: %v", headerErr, err),
+		}, nil
+	}
+	if _, err := wav.Duration(outPath); err != nil {
+		_ = os.Remove(outPath)
+		return RepairResult{
+			Status: RepairStatusUnrecoverable,
+			Path:   path,
+			Detail: fmt.Sprintf("re-encoded file is unreadable: %v", err),
+		}, nil
+	}
+	return RepairResult{Status: RepairStatusReencoded, Path: outPath, Detail: fmt.Sprintf("header repair failed: %v", headerErr)}, nil
+}
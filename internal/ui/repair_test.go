@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepairRecording(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	t.Setenv("LOOPBACK_NOTES_FFMPEG_BIN", filepath.Join(t.TempDir(), "missing-ffmpeg.exe"))
+	dir := t.TempDir()
+
+	ok := filepath.Join(dir, "ok.wav")
+	writeTestWAV(t, ok, 16000, 1, sine(16000, 1, 0.5, 0.5))
+
+	// Same audio with the header sizes a crash leaves behind
+	crashed := filepath.Join(dir, "crashed.wav")
+	b, err := os.ReadFile(ok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(b[4:8], []byte{0, 0, 0, 0})
+	copy(b[40:44], []byte{0, 0, 0, 0})
+	if err := os.WriteFile(crashed, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cut off inside the fmt chunk: nothing left to repair
+	truncated := filepath.Join(dir, "truncated.wav")
+	if err := os.WriteFile(truncated, b[:30], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path, want string
+	}{
+		{ok, RepairStatusOK},
+		{crashed, RepairStatusRepaired},
+		{truncated, RepairStatusUnrecoverable},
+	}
+	for _, tt := range tests {
+		res, err := a.RepairRecording(tt.path)
+		if err != nil {
+			t.Fatalf("%s: %v", filepath.Base(tt.path), err)
+		}
+		if res.Status != tt.want || res.Path != tt.path {
+			t.Errorf("%s: got %+v, want status %q", filepath.Base(tt.path), res, tt.want)
+		}
+	}
+	if _, err := a.RepairRecording(filepath.Join(dir, "nope.wav")); err == nil {
+		t.Error("missing file accepted")
+	}
+}
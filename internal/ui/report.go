@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"blackbox/internal/pathx"
+)
+
+// reportTemplate is a self-contained page: inline styles, the recording as a
+// data URL and the transcript in a collapsible section.
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: "Segoe UI", sans-serif; max-width: 860px; margin: 2em auto; padding: 0 1em; color: #222; }
+h1 { font-size: 1.5em; }
+table.meta td { padding: 2px 12px 2px 0; color: #555; }
+audio { width: 100%; margin: 1em 0; }
+.summary, .transcript { white-space: pre-wrap; line-height: 1.5; }
+details { margin-top: 1.5em; }
+summary { cursor: pointer; font-weight: 600; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table class="meta">
+<tr><td>Recorded</td><td>{{.Recorded}}</td></tr>
+{{if .Duration}}<tr><td>Duration</td><td>{{.Duration}}</td></tr>
+{{end}}<tr><td>File</td><td>{{.File}}</td></tr>
+</table>
+{{if .Audio}}<audio controls src="{{.Audio}}"></audio>
+{{end}}{{if .Summary}}<h2>Summary</h2>
+<div class="summary">{{.Summary}}</div>
+{{end}}{{if .Transcript}}<details>
+<summary>Transcript</summary>
+<div class="transcript">{{.Transcript}}</div>
+</details>
+{{end}}</body>
+</html>
+`))
+
+type reportData struct {
+	Title      string
+	Recorded   string
+	Duration   string
+	File       string
+	Audio      template.URL
+	Summary    string
+	Transcript string
+}
+
+// ExportHTML writes a self-contained HTML page for a recording: its details,
+// an embedded audio player, the summary and a collapsible transcript. The
+// transcript and summary are looked up by base name in the transcript
+// directory. An empty destPath writes <base>.html there. Returns the path written.
+func (a *App) ExportHTML(audioPath, destPath string) (string, error) {
+	if strings.TrimSpace(audioPath) == "" {
+		return "", errors.New("recording path required")
+	}
+	info, err := os.Stat(pathx.Long(audioPath))
+	if err != nil {
+		return "", err
+	}
+	dir := a.settings.Get().transcriptDir()
+	base := strings.TrimSuffix(filepath.Base(audioPath), filepath.Ext(audioPath))
+	if strings.TrimSpace(destPath) == "" {
+		destPath = filepath.Join(dir, base+".html")
+	}
+
+	data := reportData{
+		Title: base,
+		File:  filepath.Base(audioPath),
+	}
+	at, err := time.ParseInLocation(recordingTimeLayout, base, time.Local)
+	if err != nil {
+		at = info.ModTime()
+	}
+	data.Recorded = at.Format("Monday, 2 January 2006 15:04")
+	if d := wavDuration(audioPath); d > 0 {
+		data.Duration = d.Round(time.Second).String()
+	}
+
+	url, err := a.GetAudioDataURL(audioPath)
+	if err != nil {
+		return "", err
+	}
+	data.Audio = template.URL(url)
+
+	data.Transcript, data.Summary = recordingTexts(dir, base)
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render report: %w", err)
+	}
+	if err := os.MkdirAll(pathx.Long(filepath.Dir(destPath)), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(pathx.Long(destPath), buf.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("write report: %w", err)
+	}
+	return destPath, nil
+}
+
+// recordingTexts returns the transcript and summary stored in dir for the
+// recording named base; either is empty if missing. A separate
+// <base>_summary.txt wins over a summary appended to the transcript.
+func recordingTexts(dir, base string) (transcript, summary string) {
+	if b, err := os.ReadFile(pathx.Long(filepath.Join(dir, base+".txt"))); err == nil {
+		text := string(b)
+		transcript = strings.TrimSpace(stripAppendedSummary(text))
+		if i := strings.Index(text, transcriptSummaryDelimiter); i >= 0 {
+			summary = strings.TrimSpace(text[i+len(transcriptSummaryDelimiter):])
+		}
+	}
+	if b, err := os.ReadFile(pathx.Long(filepath.Join(dir, base+"_summary.txt"))); err == nil {
+		summary = strings.TrimSpace(string(b))
+	}
+	return transcript, summary
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportHTML(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	dir := a.settings.Get().transcriptDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	wavPath := filepath.Join(dir, "20261016_093000.wav")
+	writeTestWAV(t, wavPath, 16000, 1, sine(16000, 1, 2, 0.5))
+	transcript := `Alice said <script>alert("x")</script> & left.`
+	if err := os.WriteFile(filepath.Join(dir, "20261016_093000.txt"), []byte(transcript+transcriptSummaryDelimiter+"Use <b>bold</b> plans.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := a.ExportHTML(wavPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "20261016_093000.html"); path != want {
+		t.Errorf("written to %s, want %s", path, want)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(b)
+
+	for _, want := range []string{
+		`<audio controls src="data:audio/wav;base64,UklGR`,
+		`Alice said &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; left.`,
+		`Use &lt;b&gt;bold&lt;/b&gt; plans.`,
+		`Friday, 16 October 2026 09:30`,
+		`<td>Duration</td><td>2s</td>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+	for _, unsafe := range []string{"<script>", "<b>bold"} {
+		if strings.Contains(page, unsafe) {
+			t.Errorf("page contains unescaped %q", unsafe)
+		}
+	}
+	// The appended summary belongs in the summary section only
+	if i := strings.Index(page, `<div class="transcript">`); i < 0 || strings.Contains(page[i:], "plans.") {
+		t.Error("appended summary rendered in the transcript")
+	}
+}
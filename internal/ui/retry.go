@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LLM retry defaults
+const (
+	defaultMaxRetries = 3
+	retryBaseDelay    = time.Second
+	retryMaxDelay     = 30 * time.Second
+	retryMaxShift     = 5 // retryBaseDelay<<5 already exceeds retryMaxDelay
+)
+
+// newAPIStatusError builds an apiStatusError from a non-200 response,
+// capturing any Retry-After hint.
+func newAPIStatusError(resp *http.Response, body []byte) *apiStatusError {
+	return &apiStatusError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// retryable reports whether err is a rate limit or transient server error.
+func retryable(err error) (*apiStatusError, bool) {
+	var statusErr *apiStatusError
+	if !errors.As(err, &statusErr) {
+		return nil, false
+	}
+	switch statusErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable:
+		return statusErr, true
+	}
+	return nil, false
+}
+
+// retryDelay returns the wait before retry attempt (0-based): Retry-After when
+// the server gave one, else exponential backoff with jitter, capped at retryMaxDelay.
+func retryDelay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, 2*retryMaxDelay)
+	}
+	// Clamp the shift so large attempt counts can't overflow time.Duration
+	d := min(retryBaseDelay<<min(max(attempt, 0), retryMaxShift), retryMaxDelay)
+	// Full jitter over the upper half avoids synchronised retries
+	return d/2 + rand.N(d/2+1)
+}
+
+// withRetries calls do, retrying 429/500/502/503 responses up to maxRetries
+// times. Other errors are returned immediately. do reports whether it has
+// already delivered output (e.g. streamed deltas), after which it isn't retried.
+func withRetries(ctx context.Context, maxRetries int, do func() (string, bool, error)) (string, error) {
+	for attempt := 0; ; attempt++ {
+		out, delivered, err := do()
+		statusErr, ok := retryable(err)
+		if err == nil || !ok || delivered || attempt >= maxRetries {
+			return out, err
+		}
+		select {
+		case <-time.After(retryDelay(attempt, statusErr.RetryAfter)):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// maxRetries returns the configured retry count: 0 uses the default,
+// negative disables retries.
+func (c *llmConfig) maxRetries() int {
+	switch {
+	case c.MaxRetries < 0:
+		return 0
+	case c.MaxRetries == 0:
+		return defaultMaxRetries
+	}
+	return c.MaxRetries
+}
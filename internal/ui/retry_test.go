@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter time.Duration
+		lo, hi     time.Duration
+	}{
+		{"first attempt", 0, 0, retryBaseDelay / 2, retryBaseDelay},
+		{"third attempt", 2, 0, 2 * time.Second, 4 * time.Second},
+		{"capped", 10, 0, retryMaxDelay / 2, retryMaxDelay},
+		{"shift overflow", 40, 0, retryMaxDelay / 2, retryMaxDelay},
+		{"huge attempt", 1 << 20, 0, retryMaxDelay / 2, retryMaxDelay},
+		{"negative attempt", -1, 0, retryBaseDelay / 2, retryBaseDelay},
+		{"retry-after honoured", 0, 7 * time.Second, 7 * time.Second, 7 * time.Second},
+		{"retry-after capped", 0, time.Hour, 2 * retryMaxDelay, 2 * retryMaxDelay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 50 {
+				d := retryDelay(tt.attempt, tt.retryAfter)
+				if d < tt.lo || d > tt.hi {
+					t.Fatalf("retryDelay(%d, %v) = %v, want [%v, %v]", tt.attempt, tt.retryAfter, d, tt.lo, tt.hi)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"  ", 0},
+		{"5", 5 * time.Second},
+		{" 120 ", 2 * time.Minute},
+		{"0", 0},
+		{"-3", 0},
+		{"soon", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.in, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"blackbox/internal/pathx"
+)
+
+// summarySnippetRadius is how many characters of context surround a match.
+const summarySnippetRadius = 80
+
+// SummaryMatch is a summary containing a searched phrase.
+type SummaryMatch struct {
+	SummaryPath    string    `json:"summary_path"`
+	TranscriptPath string    `json:"transcript_path"` // empty if the transcript is missing
+	Snippet        string    `json:"snippet"`
+	ModifiedAt     time.Time `json:"modified_at"`
+}
+
+// SearchSummaries finds summaries in TranscriptDir containing query
+// (case-insensitive), both <base>_summary.txt files and summaries appended to
+// transcripts. Results are newest first; limit <= 0 returns all after offset.
+func (a *App) SearchSummaries(query string, limit, offset int) ([]SummaryMatch, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("query required")
+	}
+	dir := a.settings.Get().transcriptDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Match case-insensitively on the original text so offsets line up with it
+	needle := regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+	var matches []SummaryMatch
+	byTranscript := map[string]int{} // index into matches, for summary_output "both"
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(name), ".txt") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		b, err := os.ReadFile(pathx.Long(path))
+		if err != nil {
+			continue
+		}
+
+		var summary, transcriptPath string
+		base := strings.TrimSuffix(name, filepath.Ext(name))
+		if strings.HasSuffix(base, "_summary") {
+			summary = string(b)
+			candidate := filepath.Join(dir, strings.TrimSuffix(base, "_summary")+".txt")
+			if _, err := os.Stat(pathx.Long(candidate)); err == nil {
+				transcriptPath = candidate
+			}
+		} else if i := strings.Index(string(b), transcriptSummaryDelimiter); i >= 0 {
+			summary = string(b)[i+len(transcriptSummaryDelimiter):]
+			transcriptPath = path
+		} else {
+			continue
+		}
+
+		summary = stripSummaryNotes(summary)
+		loc := needle.FindStringIndex(summary)
+		if loc == nil {
+			continue
+		}
+		match := SummaryMatch{
+			SummaryPath:    path,
+			TranscriptPath: transcriptPath,
+			Snippet:        snippetAround(summary, loc[0], loc[1]-loc[0]),
+			ModifiedAt:     info.ModTime(),
+		}
+		// The same summary may be both in <base>_summary.txt and appended to
+		// the transcript; list the recording once, preferring the summary file
+		if transcriptPath != "" {
+			if i, ok := byTranscript[transcriptPath]; ok {
+				if matches[i].SummaryPath == transcriptPath {
+					matches[i] = match
+				}
+				continue
+			}
+			byTranscript[transcriptPath] = len(matches)
+		}
+		matches = append(matches, match)
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ModifiedAt.After(matches[j].ModifiedAt) })
+	if offset >= len(matches) {
+		return []SummaryMatch{}, nil
+	}
+	matches = matches[max(offset, 0):]
+	if limit > 0 && limit < len(matches) {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// snippetAround returns the text around s[at:at+n] on a single line, with
+// ellipses where it was cut.
+func snippetAround(s string, at, n int) string {
+	at = min(max(at, 0), len(s))
+	start := max(at-summarySnippetRadius, 0)
+	end := min(at+max(n, 0)+summarySnippetRadius, len(s))
+	// Don't split multi-byte characters
+	for start > 0 && !utf8.RuneStart(s[start]) {
+		start--
+	}
+	for end < len(s) && !utf8.RuneStart(s[end]) {
+		end++
+	}
+	snippet := strings.Join(strings.Fields(s[start:end]), " ")
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(s) {
+		snippet += "…"
+	}
+	return snippet
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSearchSummaries(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	dir := a.settings.Get().transcriptDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		// summary_output "both": the same summary twice for one recording
+		"standup.txt":         "hello" + transcriptSummaryDelimiter + "Agreed the Budget for Q3.\n",
+		"standup_summary.txt": "Agreed the Budget for Q3.\n",
+		// Runes whose lower case has a different byte length precede the match
+		"trip.txt":           "x" + transcriptSummaryDelimiter + strings.Repeat("ȺİȺİ ", 30) + "then the budget review.\n",
+		"orphan_summary.txt": "No budget talk here, transcript deleted.\n",
+		"other.txt":          "unrelated transcript with budget but no summary",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches, err := a.SearchSummaries("BUDGET", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]SummaryMatch{}
+	for _, m := range matches {
+		got[filepath.Base(m.SummaryPath)] = m
+	}
+	if len(matches) != 3 {
+		t.Fatalf("got %d matches, want 3: %+v", len(matches), matches)
+	}
+	if m, ok := got["standup_summary.txt"]; !ok || filepath.Base(m.TranscriptPath) != "standup.txt" {
+		t.Errorf("standup: %+v", got)
+	}
+	if m := got["trip.txt"]; !strings.Contains(m.Snippet, "budget review") {
+		t.Errorf("trip snippet misaligned: %q", m.Snippet)
+	}
+	if m, ok := got["orphan_summary.txt"]; !ok || m.TranscriptPath != "" {
+		t.Errorf("orphan: %+v", m)
+	}
+
+	if page, _ := a.SearchSummaries("budget", 1, 2); len(page) != 1 {
+		t.Errorf("limit/offset returned %d matches", len(page))
+	}
+	if page, _ := a.SearchSummaries("budget", 0, 5); len(page) != 0 {
+		t.Errorf("offset past the end returned %d matches", len(page))
+	}
+	if _, err := a.SearchSummaries("  ", 0, 0); err == nil {
+		t.Error("empty query accepted")
+	}
+	if m, _ := a.SearchSummaries("q3.", 0, 0); len(m) != 1 {
+		t.Errorf("query with regexp metacharacters matched %d summaries", len(m))
+	}
+}
+
+func TestSnippetAround(t *testing.T) {
+	long := strings.Repeat("a", 200) + " needle " + strings.Repeat("é", 200)
+	tests := []struct {
+		name  string
+		s     string
+		at, n int
+		want  string
+	}{
+		{"whole", "find the needle here", 9, 6, "find the needle here"},
+		{"past end", "short", 10, 6, "short"},
+		{"negative", "short", -3, 2, "short"},
+		{"long n", "short", 2, 100, "short"},
+		{"cut both sides", long, 201, 6, "…" + strings.Repeat("a", 79) + " needle " + strings.Repeat("é", 40) + "…"},
+	}
+	for _, tt := range tests {
+		if got := snippetAround(tt.s, tt.at, tt.n); got != tt.want {
+			t.Errorf("%s: snippetAround = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -5,18 +5,183 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 )
 
 // UISettings holds configurable UI preferences.
 type UISettings struct {
 	OutDir string `json:"out_dir"`
+	// TranscriptDir holds whisper transcripts/logs and summaries. Empty means OutDir.
+	TranscriptDir string `json:"transcript_dir"`
 	// Local AI settings
 	UseLocalAI   bool    `json:"use_local_ai"`
 	LlamaTemp    float64 `json:"llama_temp"`
 	LlamaContext int     `json:"llama_context"`
 	LlamaModel   string  `json:"llama_model"`
 	LlamaAPIKey  string  `json:"llama_api_key"`
+
+	// SummaryOutput controls where summaries are written:
+	// "separate" (<base>_summary.txt), "append" (appended to the transcript .txt) or "both".
+	SummaryOutput string `json:"summary_output"`
+
+	// SummaryChunkTokens splits transcripts longer than this many approximate
+	// tokens into parts for remote summarisation, then combines the part
+	// summaries. 0 sends the whole transcript. Local AI derives the size from LlamaContext.
+	SummaryChunkTokens int `json:"summary_chunk_tokens"`
+
+	// IncludePreviousSummary sends an existing summary as extra context so a
+	// re-summarisation refines it rather than starting fresh.
+	IncludePreviousSummary bool `json:"include_previous_summary"`
+
+	// SummaryTokenBudget caps the transcript sent for summarisation, in approximate
+	// tokens. Longer transcripts keep only their most recent part. 0 disables trimming.
+	SummaryTokenBudget int `json:"summary_token_budget"`
+
+	// RedactRemote scrubs emails, phone numbers and RedactPatterns from the
+	// transcript before it is sent to a remote LLM. Local summaries are unaffected.
+	RedactRemote bool `json:"redact_remote"`
+
+	// RedactPatterns are extra regular expressions (Go RE2 syntax) to redact.
+	RedactPatterns []string `json:"redact_patterns"`
+
+	// LoopbackDevice selects the render device to capture, by ID or name
+	// substring (see ListRenderDevices). Empty uses the default device.
+	LoopbackDevice string `json:"loopback_device"`
+
+	// LoopbackProcess limits loopback capture to one application (exe name or PID)
+	// when the build supports per-process loopback. Empty captures all system audio.
+	LoopbackProcess string `json:"loopback_process"`
+
+	// Channels is the recording channel count: 1 (mono, default) or 2 (stereo).
+	Channels int `json:"channels"`
+
+	// WhisperModel is the whisper model used for transcription: a file name in
+	// the models directory (see ListWhisperModels) or a full path.
+	// Empty uses ggml-base.en.bin.
+	WhisperModel string `json:"whisper_model"`
+
+	// Language is the whisper language code (e.g. "en", "de" or "auto").
+	// Empty uses "en". SetRecordingLanguage overrides it per recording.
+	Language string `json:"language"`
+
+	// Subtitles has Transcribe also write <base>.srt and <base>.vtt captions.
+	Subtitles bool `json:"subtitles"`
+
+	// Confidence has Transcribe also write whisper's full JSON (<base>.json),
+	// from which GetTranscriptConfidence scores the transcript.
+	Confidence bool `json:"confidence"`
+
+	// TranscribeOnStartup transcribes, in the background at launch, every
+	// recording in OutDir that has no transcript yet. Silent recordings are skipped.
+	TranscribeOnStartup bool `json:"transcribe_on_startup"`
+
+	// SkipWhisperConversion sends recordings to whisper as-is instead of via a
+	// temporary 16 kHz mono copy when they were captured at another rate or
+	// channel count. The stored recording is never modified either way.
+	SkipWhisperConversion bool `json:"skip_whisper_conversion"`
+
+	// ChunkParallelism is how many chunks TranscribeChunked runs through whisper
+	// at once (capped at the CPU count). 0 or 1 transcribes serially.
+	ChunkParallelism int `json:"chunk_parallelism"`
+
+	// AutoSplit starts a new timestamped recording file after
+	// AutoSplitGapSeconds of audio below AutoSplitSilenceDBFS, once there has
+	// been sound since the last split. Multitrack recordings aren't split.
+	AutoSplit            bool    `json:"auto_split"`
+	AutoSplitSilenceDBFS float64 `json:"auto_split_silence_dbfs"` // 0 uses the default (-50)
+	AutoSplitGapSeconds  int     `json:"auto_split_gap_seconds"`  // 0 uses the default (60)
+
+	// NoiseGate silences the microphone while its level is below
+	// NoiseGateDBFS (e.g. fan hum between phrases), fading in and out to avoid
+	// chopping words. It applies before the mic is written or mixed.
+	NoiseGate     bool    `json:"noise_gate"`
+	NoiseGateDBFS float64 `json:"noise_gate_dbfs"` // 0 uses the default (-50)
+
+	// MaxFileMinutes and MaxFileMB cap one recording file; past either, the
+	// recording continues in <base>_part02, _part03 and so on. MB counts
+	// uncompressed audio. 0 means no cap. Multitrack recordings aren't rotated.
+	MaxFileMinutes int `json:"max_file_minutes"`
+	MaxFileMB      int `json:"max_file_mb"`
+
+	// AudioDataFPS is how many "audioData" events a second are sent to the
+	// spectrum analyser while recording, each downsampled from the buffers
+	// captured since the last. 0 uses the default (30); negative disables them.
+	AudioDataFPS int `json:"audio_data_fps"`
+
+	// AudioCacheMB caps the in-memory cache of audio data URLs served to the
+	// player, in MiB. 0 uses the default (64); negative disables caching.
+	AudioCacheMB int `json:"audio_cache_mb"`
+
+	// AudioFormat selects the recording output: "wav" (default), "opus" (Ogg/Opus)
+	// or "flac" (lossless); both compressed formats are encoded through ffmpeg.
+	AudioFormat string `json:"audio_format"`
+}
+
+// Summary output modes
+const (
+	SummaryOutputSeparate = "separate"
+	SummaryOutputAppend   = "append"
+	SummaryOutputBoth     = "both"
+)
+
+// applyDefaults fills in defaults for fields that are unset.
+func applyDefaults(cfg *UISettings) {
+	if cfg.OutDir == "" {
+		cfg.OutDir = "./out"
+	}
+	if cfg.LlamaTemp == 0 {
+		cfg.LlamaTemp = 0.1
+	}
+	if cfg.LlamaContext == 0 {
+		cfg.LlamaContext = 32000
+	}
+	if cfg.SummaryTokenBudget < 0 {
+		cfg.SummaryTokenBudget = 0
+	}
+	if cfg.SummaryChunkTokens < 0 {
+		cfg.SummaryChunkTokens = 0
+	}
+	if cfg.Language = strings.ToLower(strings.TrimSpace(cfg.Language)); !whisperLanguage.MatchString(cfg.Language) {
+		cfg.Language = defaultLanguage
+	}
+	if cfg.AutoSplitSilenceDBFS == 0 {
+		cfg.AutoSplitSilenceDBFS = defaultAutoSplitSilenceDBFS
+	}
+	if cfg.AutoSplitGapSeconds <= 0 {
+		cfg.AutoSplitGapSeconds = defaultAutoSplitGapSeconds
+	}
+	if cfg.NoiseGateDBFS == 0 {
+		cfg.NoiseGateDBFS = defaultNoiseGateDBFS
+	}
+	if cfg.AudioCacheMB == 0 {
+		cfg.AudioCacheMB = defaultAudioCacheMB
+	}
+	if cfg.ChunkParallelism < 0 {
+		cfg.ChunkParallelism = 0
+	}
+	if cfg.Channels != 2 {
+		cfg.Channels = 1
+	}
+	switch cfg.AudioFormat {
+	case AudioFormatWAV, AudioFormatOpus, AudioFormatFLAC:
+	default:
+		cfg.AudioFormat = AudioFormatWAV
+	}
+	switch cfg.SummaryOutput {
+	case SummaryOutputSeparate, SummaryOutputAppend, SummaryOutputBoth:
+	default:
+		cfg.SummaryOutput = SummaryOutputSeparate
+	}
+}
+
+// transcriptDir returns the directory for transcripts and summaries,
+// falling back to OutDir when TranscriptDir is unset.
+func (s UISettings) transcriptDir() string {
+	if s.TranscriptDir != "" {
+		return s.TranscriptDir
+	}
+	return s.OutDir
 }
 
 type SettingsStore struct {
@@ -44,14 +209,8 @@ func (s *SettingsStore) load() error {
 	}
 	if _, err := os.Stat(s.path); err != nil {
 		// Default settings
-		s.settings = UISettings{
-			OutDir:       "./out",
-			UseLocalAI:   false,
-			LlamaTemp:    0.1,
-			LlamaContext: 32000,
-			LlamaModel:   "",
-			LlamaAPIKey:  "",
-		}
+		s.settings = UISettings{}
+		applyDefaults(&s.settings)
 		// Ensure directory exists for first save
 		_ = os.MkdirAll(filepath.Dir(s.path), 0755)
 		return nil
@@ -64,16 +223,8 @@ func (s *SettingsStore) load() error {
 	if err := json.Unmarshal(b, &cfg); err != nil {
 		return err
 	}
-	if cfg.OutDir == "" {
-		cfg.OutDir = "./out"
-	}
 	// Set defaults for new fields if not present
-	if cfg.LlamaTemp == 0 {
-		cfg.LlamaTemp = 0.1
-	}
-	if cfg.LlamaContext == 0 {
-		cfg.LlamaContext = 32000
-	}
+	applyDefaults(&cfg)
 	s.settings = cfg
 	return nil
 }
@@ -82,16 +233,8 @@ func (s *SettingsStore) load() error {
 func (s *SettingsStore) Save(newSettings UISettings) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if newSettings.OutDir == "" {
-		newSettings.OutDir = "./out"
-	}
 	// Set defaults for new fields if not present
-	if newSettings.LlamaTemp == 0 {
-		newSettings.LlamaTemp = 0.1
-	}
-	if newSettings.LlamaContext == 0 {
-		newSettings.LlamaContext = 32000
-	}
+	applyDefaults(&newSettings)
 	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
 		return err
 	}
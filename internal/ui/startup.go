@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"blackbox/internal/pathx"
+)
+
+// untranscribedRecordings returns recordings in OutDir with no <base>.txt in
+// the transcript directory, newest first.
+func (a *App) untranscribedRecordings() ([]RecordingInfo, error) {
+	recs, err := a.listRecordings()
+	if err != nil {
+		return nil, err
+	}
+	dir := a.settings.Get().transcriptDir()
+	var missing []RecordingInfo
+	for _, r := range recs {
+		base := strings.TrimSuffix(r.Name, filepath.Ext(r.Name))
+		if _, err := os.Stat(pathx.Long(filepath.Join(dir, base+".txt"))); err == nil {
+			continue
+		}
+		missing = append(missing, r)
+	}
+	return missing, nil
+}
+
+// transcribeMissing transcribes every untranscribed, non-silent recording in
+// the background, ChunkParallelism at a time. CancelTranscribe stops the batch.
+func (a *App) transcribeMissing() {
+	recs, err := a.untranscribedRecordings()
+	if err != nil {
+		log.Printf("startup transcription: %v", err)
+		return
+	}
+	workers := chunkWorkers(a.settings.Get().ChunkParallelism)
+	threads := whisperThreads(workers)
+	a.transcribeEach(recs, workers, func(path string) error {
+		_, err := a.transcribe(path, "", threads)
+		return err
+	})
+}
+
+// transcribeEach calls transcribe for each non-silent recording in recs, with
+// at most workers running at once.
+func (a *App) transcribeEach(recs []RecordingInfo, workers int, transcribe func(path string) error) {
+	if len(recs) == 0 {
+		return
+	}
+	ctx, done := a.transcribeOps.begin()
+	defer done()
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				if silent, err := a.IsSilentRecording(path); err == nil && silent {
+					continue
+				}
+				if err := transcribe(path); err != nil && ctx.Err() == nil {
+					log.Printf("startup transcription of %s: %v", path, err)
+				}
+			}
+		}()
+	}
+	for _, r := range recs {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- r.Path
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestTranscribeMissingQueuesUntranscribed(t *testing.T) {
+	a := newTestApp(t, UISettings{})
+	cfg := a.settings.Get()
+	if err := os.MkdirAll(cfg.OutDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestWAV(t, filepath.Join(cfg.OutDir, "done.wav"), 16000, 1, sine(16000, 1, 1, 0.5))
+	writeTestWAV(t, filepath.Join(cfg.OutDir, "new.wav"), 16000, 1, sine(16000, 1, 1, 0.5))
+	writeTestWAV(t, filepath.Join(cfg.OutDir, "silent.wav"), 16000, 1, make([]int16, 16000))
+	writeTestWAV(t, filepath.Join(cfg.OutDir, "stereo.wav"), 48000, 2, sine(48000, 2, 1, 0.5))
+	if err := os.WriteFile(filepath.Join(cfg.transcriptDir(), "done.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Not a recording
+	if err := os.WriteFile(filepath.Join(cfg.OutDir, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := a.untranscribedRecordings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var queued []string
+	a.transcribeEach(recs, 2, func(path string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		queued = append(queued, filepath.Base(path))
+		return nil
+	})
+	slices.Sort(queued)
+	if want := []string{"new.wav", "stereo.wav"}; !slices.Equal(queued, want) {
+		t.Errorf("queued %v, want %v", queued, want)
+	}
+}
+
+func TestWhisperThreads(t *testing.T) {
+	if got := whisperThreads(1); got != 0 {
+		t.Errorf("whisperThreads(1) = %d, want 0 (whisper default)", got)
+	}
+	for _, workers := range []int{2, 3, 64, 1 << 20} {
+		if got := whisperThreads(workers); got < 1 || got*workers > max(workers, runtime.NumCPU()) {
+			t.Errorf("whisperThreads(%d) = %d", workers, got)
+		}
+	}
+}
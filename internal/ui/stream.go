@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	wruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// streamIdleTimeout aborts a streamed completion when the server goes quiet.
+// There is no overall limit, since long summaries can stream for a while.
+const streamIdleTimeout = 120 * time.Second
+
+// SummaryChunk is emitted as a "summaryChunk" event for each streamed delta.
+type SummaryChunk struct {
+	Text   string `json:"text"`
+	Source string `json:"source"` // transcript being summarised
+}
+
+// streamChunk is one SSE data payload of a streamed chat completion.
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// makeOpenAIRequestStream is makeOpenAIRequest with "stream": true, calling
+// onDelta with each piece of content as it arrives and returning the full text.
+// If the server replies with a regular JSON completion instead of an event
+// stream, that response is used as-is.
+func makeOpenAIRequestStream(ctx context.Context, cfg *llmConfig, request chatRequest, onDelta func(string)) (string, error) {
+	request.Stream = true
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", cfg.chatCompletionsURL(), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	cfg.setAuthHeader(req)
+
+	idle := time.AfterFunc(streamIdleTimeout, cancel)
+	defer idle.Stop()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", newAPIStatusError(resp, body)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/event-stream" {
+		// Server ignored "stream"; treat it as a normal completion
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("failed to read response: %w", err)
+		}
+		return parseChatResponse(body)
+	}
+
+	// Lines are read whole before decoding, so multi-byte characters split
+	// across network reads are reassembled before they reach onDelta.
+	var sb strings.Builder
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		idle.Reset(streamIdleTimeout)
+		line = bytes.TrimSpace(line)
+		if data, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+			data = bytes.TrimSpace(data)
+			if string(data) == "[DONE]" {
+				break
+			}
+			var chunk streamChunk
+			if err := json.Unmarshal(data, &chunk); err != nil {
+				return "", fmt.Errorf("failed to parse stream chunk: %w", err)
+			}
+			if chunk.Error != nil {
+				return "", fmt.Errorf("API error: %s", chunk.Error.Message)
+			}
+			if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+				delta := chunk.Choices[0].Delta.Content
+				sb.WriteString(delta)
+				if onDelta != nil {
+					onDelta(delta)
+				}
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			if ctx.Err() != nil {
+				return "", fmt.Errorf("stream stalled for %s", streamIdleTimeout)
+			}
+			return "", fmt.Errorf("failed to read stream: %w", readErr)
+		}
+	}
+	if sb.Len() == 0 {
+		return "", fmt.Errorf("no content in streamed response")
+	}
+	return sb.String(), nil
+}
+
+// summaryChunkEmitter returns an onDelta callback that forwards deltas for
+// source to the frontend as "summaryChunk" events.
+func (a *App) summaryChunkEmitter(source string) func(string) {
+	return func(text string) {
+		if a.uiCtx != nil {
+			wruntime.EventsEmit(a.uiCtx, "summaryChunk", SummaryChunk{Text: text, Source: source})
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"blackbox/internal/execx"
+	"blackbox/internal/pathx"
+)
+
+// TranscriptSegment is one timed caption from a transcript's SRT/VTT output.
+type TranscriptSegment struct {
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+	Text    string `json:"text"`
+}
+
+// cueTiming matches SRT ("00:00:01,000 --> 00:00:04,200") and VTT
+// ("00:00:01.000 --> 00:00:04.200", hours optional) cue timings.
+var cueTiming = regexp.MustCompile(`^(?:(\d+):)?(\d{2}):(\d{2})[.,](\d{3})\s+-->\s+(?:(\d+):)?(\d{2}):(\d{2})[.,](\d{3})`)
+
+// GetTranscriptSegments returns the timed segments for a transcript, read from
+// the <base>.srt (or <base>.vtt) written when the Subtitles setting is on.
+func (a *App) GetTranscriptSegments(txtPath string) ([]TranscriptSegment, error) {
+	if strings.TrimSpace(txtPath) == "" {
+		return nil, errors.New("txt path required")
+	}
+	base := strings.TrimSuffix(txtPath, filepath.Ext(txtPath))
+	for _, ext := range []string{".srt", ".vtt"} {
+		f, err := os.Open(pathx.Long(base + ext))
+		if err != nil {
+			continue
+		}
+		segments, err := parseCues(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", base+ext, err)
+		}
+		return segments, nil
+	}
+	return nil, fmt.Errorf("no subtitles for %s; enable the subtitles setting and transcribe again", txtPath)
+}
+
+// GetTranscriptConfidence returns a 0-1 confidence for a transcript (the
+// geometric mean of whisper's token probabilities), read from the <base>.json
+// written when the Confidence setting is on.
+func (a *App) GetTranscriptConfidence(txtPath string) (float64, error) {
+	if strings.TrimSpace(txtPath) == "" {
+		return 0, errors.New("txt path required")
+	}
+	jsonPath := strings.TrimSuffix(txtPath, filepath.Ext(txtPath)) + ".json"
+	if _, err := os.Stat(pathx.Long(jsonPath)); err != nil {
+		return 0, fmt.Errorf("no whisper JSON for %s; enable the confidence setting and transcribe again", txtPath)
+	}
+	c, err := execx.JSONConfidence(pathx.Long(jsonPath))
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", jsonPath, err)
+	}
+	return c, nil
+}
+
+// parseCues reads SRT or VTT cues. Cue numbers, the WEBVTT header and notes
+// are skipped; multi-line cue text is joined with spaces.
+func parseCues(f *os.File) ([]TranscriptSegment, error) {
+	var segments []TranscriptSegment
+	var cur *TranscriptSegment
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if m := cueTiming.FindStringSubmatch(line); m != nil {
+			segments = append(segments, TranscriptSegment{
+				StartMs: cueMillis(m[1:5]),
+				EndMs:   cueMillis(m[5:9]),
+			})
+			cur = &segments[len(segments)-1]
+			continue
+		}
+		if line == "" {
+			cur = nil
+			continue
+		}
+		if cur != nil {
+			if cur.Text != "" {
+				cur.Text += " "
+			}
+			cur.Text += line
+		}
+	}
+	return segments, sc.Err()
+}
+
+// cueMillis converts [hours, minutes, seconds, millis] strings to milliseconds.
+func cueMillis(parts []string) int64 {
+	var v [4]int64
+	for i, p := range parts {
+		v[i], _ = strconv.ParseInt(p, 10, 64)
+	}
+	return ((v[0]*60+v[1])*60+v[2])*1000 + v[3]
+}
@@ -0,0 +1,15 @@
+package wav
+
+import "io"
+
+// Encoder consumes interleaved PCM S16LE frames and produces an audio file.
+// Writer implements it for plain WAV; other formats (e.g. Opus via ffmpeg) can too.
+type Encoder interface {
+	io.Writer
+	// Flush forces buffered data to the output.
+	Flush() error
+	// Close finalises the file.
+	Close() error
+}
+
+var _ Encoder = (*Writer)(nil)
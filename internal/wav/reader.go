@@ -0,0 +1,196 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"blackbox/internal/pathx"
+)
+
+// Reader reads PCM WAV files by walking the RIFF chunks rather than assuming
+// a fixed 44-byte header, so files with LIST/fact chunks are handled.
+type Reader struct {
+	file          *os.File
+	sampleRate    uint32
+	channels      uint16
+	bitsPerSample uint16
+	audioFormat   uint16
+	dataOffset    int64
+	dataSize      int64
+	data          *io.SectionReader
+}
+
+// OpenReader opens path and parses its RIFF header.
+// Call Close to release the file.
+func OpenReader(path string) (*Reader, error) {
+	f, err := os.Open(pathx.Long(path))
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r, err := NewReader(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r.file = f
+	return r, nil
+}
+
+// fmt chunk format tags
+const (
+	formatPCM        = 1
+	formatFloat      = 3
+	formatExtensible = 0xFFFE
+)
+
+// NewReader parses a WAV held in r, which is size bytes long.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	var riff [12]byte
+	if _, err := r.ReadAt(riff[:], 0); err != nil {
+		return nil, fmt.Errorf("read riff header: %w", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, errors.New("not a RIFF/WAVE file")
+	}
+
+	wr := &Reader{}
+	haveFmt := false
+	offset := int64(12)
+	for offset+8 <= size {
+		var hdr [8]byte
+		if _, err := r.ReadAt(hdr[:], offset); err != nil {
+			return nil, fmt.Errorf("read chunk header: %w", err)
+		}
+		id := string(hdr[0:4])
+		chunkSize := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+		body := offset + 8
+
+		switch id {
+		case "fmt ":
+			if chunkSize < 16 {
+				return nil, fmt.Errorf("fmt chunk too small: %d bytes", chunkSize)
+			}
+			var fmtBuf [16]byte
+			if _, err := r.ReadAt(fmtBuf[:], body); err != nil {
+				return nil, fmt.Errorf("read fmt chunk: %w", err)
+			}
+			wr.audioFormat = binary.LittleEndian.Uint16(fmtBuf[0:2])
+			wr.channels = binary.LittleEndian.Uint16(fmtBuf[2:4])
+			wr.sampleRate = binary.LittleEndian.Uint32(fmtBuf[4:8])
+			wr.bitsPerSample = binary.LittleEndian.Uint16(fmtBuf[14:16])
+			// OBS and some Audacity exports use WAVE_FORMAT_EXTENSIBLE; the
+			// real format tag is the first two bytes of the SubFormat GUID.
+			if wr.audioFormat == formatExtensible && chunkSize >= 40 {
+				var sub [2]byte
+				if _, err := r.ReadAt(sub[:], body+24); err != nil {
+					return nil, fmt.Errorf("read fmt extension: %w", err)
+				}
+				wr.audioFormat = binary.LittleEndian.Uint16(sub[:])
+			}
+			if err := wr.checkFormat(); err != nil {
+				return nil, err
+			}
+			haveFmt = true
+		case "data":
+			if !haveFmt {
+				return nil, errors.New("data chunk before fmt chunk")
+			}
+			// A zero or oversized length usually means the writer never
+			// finalised the header (e.g. crash mid-recording); use what's on disk.
+			if chunkSize == 0 || body+chunkSize > size {
+				chunkSize = size - body
+			}
+			wr.dataOffset = body
+			wr.dataSize = chunkSize
+			wr.data = io.NewSectionReader(r, body, chunkSize)
+			return wr, nil
+		}
+
+		// Chunks are word-aligned; odd sizes carry a pad byte
+		offset = body + chunkSize + chunkSize%2
+	}
+	if !haveFmt {
+		return nil, errors.New("missing fmt chunk")
+	}
+	return nil, errors.New("missing data chunk")
+}
+
+// checkFormat rejects fmt values no real file has, which would otherwise
+// leave frame sizes at zero for code that reads or resamples the samples.
+// Compressed formats such as ADPCM legitimately use sub-byte sample sizes,
+// so the multiple-of-8 rule only applies to PCM and float.
+func (r *Reader) checkFormat() error {
+	switch {
+	case r.channels == 0:
+		return errors.New("invalid fmt chunk: zero channels")
+	case r.sampleRate == 0:
+		return errors.New("invalid fmt chunk: zero sample rate")
+	case (r.audioFormat == formatPCM || r.audioFormat == formatFloat) && (r.bitsPerSample == 0 || r.bitsPerSample%8 != 0):
+		return fmt.Errorf("invalid fmt chunk: %d bits per sample", r.bitsPerSample)
+	}
+	return nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (r *Reader) SampleRate() uint32 { return r.sampleRate }
+
+// Channels returns the number of interleaved channels.
+func (r *Reader) Channels() uint16 { return r.channels }
+
+// BitsPerSample returns the sample width in bits.
+func (r *Reader) BitsPerSample() uint16 { return r.bitsPerSample }
+
+// AudioFormat returns the format tag from the fmt chunk (1 = PCM), resolved
+// through the SubFormat for WAVE_FORMAT_EXTENSIBLE files.
+func (r *Reader) AudioFormat() uint16 { return r.audioFormat }
+
+// DataSize returns the size of the data chunk in bytes.
+func (r *Reader) DataSize() int64 { return r.dataSize }
+
+// DataOffset returns the file offset of the first sample byte.
+func (r *Reader) DataOffset() int64 { return r.dataOffset }
+
+// Duration returns the playback length derived from the data chunk size.
+func (r *Reader) Duration() time.Duration {
+	bytesPerSec := int64(r.sampleRate) * int64(r.channels) * int64(r.bitsPerSample) / 8
+	if bytesPerSec == 0 {
+		return 0
+	}
+	return time.Duration(r.dataSize * int64(time.Second) / bytesPerSec)
+}
+
+// Duration opens the WAV at path and returns its playback length, computed
+// from the data chunk size and the parsed format rather than the file size.
+func Duration(path string) (time.Duration, error) {
+	r, err := OpenReader(path)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	return r.Duration(), nil
+}
+
+// Read reads raw sample bytes from the data chunk.
+func (r *Reader) Read(p []byte) (int, error) { return r.data.Read(p) }
+
+// ReadAt reads raw sample bytes at off, relative to the start of the data chunk.
+func (r *Reader) ReadAt(p []byte, off int64) (int, error) { return r.data.ReadAt(p, off) }
+
+// Close closes the underlying file if the reader was opened with OpenReader.
+func (r *Reader) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,48 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestNewReaderCorruptHeader(t *testing.T) {
+	// patch returns a valid mono WAV with 16-bit header fields overwritten,
+	// given as offset/value pairs
+	patch := func(fields ...uint16) []byte {
+		b := rawWAV(1, 100, 36+100, 100)
+		for i := 0; i+1 < len(fields); i += 2 {
+			binary.LittleEndian.PutUint16(b[fields[i]:], fields[i+1])
+		}
+		return b
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string // substring, "" for a readable file
+	}{
+		{"valid", rawWAV(1, 100, 36+100, 100), ""},
+		{"zero channels", rawWAV(0, 100, 36+100, 100), "zero channels"},
+		{"zero sample rate", patch(24, 0), "zero sample rate"},
+		{"zero bits", patch(34, 0), "0 bits per sample"},
+		{"12-bit PCM", patch(34, 12), "12 bits per sample"},
+		{"4-bit ADPCM", patch(20, 0x11, 34, 4), ""},
+		{"fmt too small", patch(16, 8), "fmt chunk too small"},
+		{"no data chunk", rawWAV(1, 0, 36, 0)[:36], "missing data chunk"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReader(bytes.NewReader(tt.data), int64(len(tt.data)))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package wav
+
+import (
+	"encoding/binary"
+	"os"
+
+	"blackbox/internal/pathx"
+)
+
+// RepairHeader fixes the RIFF and data chunk sizes of a WAV whose writer never
+// finalised them (e.g. a crash mid-recording), so other players can open it.
+// Returns whether the header was changed. Files without a readable fmt and
+// data chunk return the parse error unchanged.
+func RepairHeader(path string) (bool, error) {
+	f, err := os.OpenFile(pathx.Long(path), os.O_RDWR, 0)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	size := info.Size()
+	r, err := NewReader(f, size)
+	if err != nil {
+		return false, err
+	}
+
+	var buf [4]byte
+	changed := false
+
+	// data chunk size: fix if unset or past the end of the file, or if what
+	// follows the declared data isn't another chunk
+	if _, err := f.ReadAt(buf[:], r.dataOffset-4); err != nil {
+		return false, err
+	}
+	stored := int64(binary.LittleEndian.Uint32(buf[:]))
+	avail := size - r.dataOffset
+	if block := int64(r.channels) * int64(r.bitsPerSample) / 8; block > 0 {
+		avail -= avail % block
+	}
+	if stored == 0 || stored > avail || (stored < avail && !chunkFollows(f, r.dataOffset+stored+stored%2, size)) {
+		if avail > 0xFFFFFFFF {
+			avail = 0xFFFFFFFF
+		}
+		binary.LittleEndian.PutUint32(buf[:], uint32(avail))
+		if _, err := f.WriteAt(buf[:], r.dataOffset-4); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	// RIFF size covers everything after the first 8 bytes
+	if _, err := f.ReadAt(buf[:], 4); err != nil {
+		return false, err
+	}
+	riff := size - 8
+	if riff > 0xFFFFFFFF {
+		riff = 0xFFFFFFFF
+	}
+	if int64(binary.LittleEndian.Uint32(buf[:])) != riff {
+		binary.LittleEndian.PutUint32(buf[:], uint32(riff))
+		if _, err := f.WriteAt(buf[:], 4); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	if changed {
+		return true, f.Sync()
+	}
+	return false, nil
+}
+
+// chunkFollows reports whether a plausible chunk header (four printable ASCII
+// bytes) starts at off.
+func chunkFollows(f *os.File, off, size int64) bool {
+	if off+8 > size {
+		return false
+	}
+	var id [4]byte
+	if _, err := f.ReadAt(id[:], off); err != nil {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x20 || c > 0x7E {
+			return false
+		}
+	}
+	return true
+}
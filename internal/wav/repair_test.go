@@ -0,0 +1,78 @@
+package wav
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// rawWAV returns a 16-bit PCM WAV with dataLen bytes of samples and the given
+// header sizes, as a writer that crashed or was cut off would leave it.
+func rawWAV(channels uint16, dataLen int, riffSize, dataSize uint32) []byte {
+	b := make([]byte, 44+dataLen)
+	copy(b[0:], "RIFF")
+	binary.LittleEndian.PutUint32(b[4:], riffSize)
+	copy(b[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(b[16:], 16)
+	binary.LittleEndian.PutUint16(b[20:], 1)
+	binary.LittleEndian.PutUint16(b[22:], channels)
+	binary.LittleEndian.PutUint32(b[24:], 16000)
+	binary.LittleEndian.PutUint32(b[28:], 16000*uint32(channels)*2)
+	binary.LittleEndian.PutUint16(b[32:], channels*2)
+	binary.LittleEndian.PutUint16(b[34:], 16)
+	copy(b[36:], "data")
+	binary.LittleEndian.PutUint32(b[40:], dataSize)
+	for i := 44; i < len(b); i++ {
+		b[i] = byte(i)
+	}
+	return b
+}
+
+func TestRepairHeader(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		wantChanged bool
+		wantErr     bool
+		wantData    uint32 // data chunk size after repair
+	}{
+		{"finalised", rawWAV(1, 1000, 36+1000, 1000), false, false, 1000},
+		{"never finalised", rawWAV(1, 1000, 0, 0), true, false, 1000},
+		{"truncated copy", rawWAV(1, 600, 36+1000, 1000), true, false, 600},
+		{"partial stereo frame", rawWAV(2, 1002, 0, 0), true, false, 1000},
+		{"cut inside header", rawWAV(1, 0, 0, 0)[:30], false, true, 0},
+		{"not a wav", []byte("ID3\x04 definitely not a RIFF file"), false, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rec.wav")
+			if err := os.WriteFile(path, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			changed, err := RepairHeader(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RepairHeader error = %v, want error %v", err, tt.wantErr)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if tt.wantErr {
+				return
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := binary.LittleEndian.Uint32(b[40:]); got != tt.wantData {
+				t.Errorf("data size = %d, want %d", got, tt.wantData)
+			}
+			if got, want := binary.LittleEndian.Uint32(b[4:]), uint32(len(b)-8); got != want {
+				t.Errorf("RIFF size = %d, want %d", got, want)
+			}
+			if _, err := Duration(path); err != nil {
+				t.Errorf("repaired file unreadable: %v", err)
+			}
+		})
+	}
+}
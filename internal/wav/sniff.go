@@ -0,0 +1,49 @@
+package wav
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"blackbox/internal/pathx"
+)
+
+// SniffLen is the number of leading bytes Sniff needs to identify a file.
+const SniffLen = 12
+
+// Sniff identifies an audio container from its leading bytes. ok is true only
+// for RIFF/WAVE data this package can read; format still names other common
+// containers ("ogg", "flac", "mp3", "mp4") so callers can route them to a
+// transcoder, and is "" when unrecognised.
+func Sniff(data []byte) (format string, ok bool) {
+	switch {
+	case len(data) >= 12 && bytes.Equal(data[0:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WAVE")):
+		return "wav", true
+	case bytes.HasPrefix(data, []byte("OggS")):
+		return "ogg", false
+	case bytes.HasPrefix(data, []byte("fLaC")):
+		return "flac", false
+	case bytes.HasPrefix(data, []byte("ID3")),
+		len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0:
+		return "mp3", false
+	case len(data) >= 8 && bytes.Equal(data[4:8], []byte("ftyp")):
+		return "mp4", false
+	}
+	return "", false
+}
+
+// SniffFile reads the start of path and calls Sniff.
+func SniffFile(path string) (format string, ok bool, err error) {
+	f, err := os.Open(pathx.Long(path))
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+	buf := make([]byte, SniffLen)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", false, err
+	}
+	format, ok = Sniff(buf[:n])
+	return format, ok, nil
+}
@@ -0,0 +1,53 @@
+package wav
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []byte
+		wantFormat string
+		wantOK     bool
+	}{
+		{"wav", []byte("RIFF\x24\x00\x00\x00WAVEfmt \x10\x00\x00\x00"), "wav", true},
+		{"wav header only", []byte("RIFF\x00\x00\x00\x00WAVE"), "wav", true},
+		{"riff but not wave", []byte("RIFF\x00\x00\x00\x00AVI LIST"), "", false},
+		{"truncated riff", []byte("RIFF\x00\x00\x00\x00WAV"), "", false},
+		{"ogg", []byte("OggS\x00\x02\x00\x00\x00\x00\x00\x00"), "ogg", false},
+		{"flac", []byte("fLaC\x00\x00\x00\x22"), "flac", false},
+		{"mp3 id3", []byte("ID3\x04\x00\x00\x00\x00\x00\x00"), "mp3", false},
+		{"mp3 frame sync", []byte{0xFF, 0xFB, 0x90, 0x64}, "mp3", false},
+		{"mp4", []byte("\x00\x00\x00\x20ftypM4A "), "mp4", false},
+		{"text", []byte("hello world!"), "", false},
+		{"empty", nil, "", false},
+		{"single 0xFF", []byte{0xFF}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, ok := Sniff(tt.data)
+			if format != tt.wantFormat || ok != tt.wantOK {
+				t.Errorf("Sniff = (%q, %v), want (%q, %v)", format, ok, tt.wantFormat, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSniffFile(t *testing.T) {
+	dir := t.TempDir()
+	// An Ogg file with a .wav name is still reported as Ogg
+	misnamed := filepath.Join(dir, "meeting.wav")
+	if err := os.WriteFile(misnamed, []byte("OggS\x00\x02"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	format, ok, err := SniffFile(misnamed)
+	if err != nil || format != "ogg" || ok {
+		t.Errorf("SniffFile = (%q, %v, %v), want (\"ogg\", false, nil)", format, ok, err)
+	}
+	if _, _, err := SniffFile(filepath.Join(dir, "missing.wav")); err == nil {
+		t.Error("SniffFile on a missing file returned no error")
+	}
+}
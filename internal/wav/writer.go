@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+
+	"blackbox/internal/pathx"
 )
 
 // Writer writes a PCM WAV file with a correct RIFF header.
@@ -26,7 +28,7 @@ func NewWriter(path string, sampleRate uint32, channels, bitsPerSample uint16) (
 	if bitsPerSample != 16 {
 		return nil, fmt.Errorf("only 16-bit PCM supported, got %d", bitsPerSample)
 	}
-	f, err := os.Create(path)
+	f, err := os.Create(pathx.Long(path))
 	if err != nil {
 		return nil, err
 	}
@@ -28,6 +28,12 @@ func main() {
 		BackgroundColour: &options.RGBA{R: 20, G: 20, B: 20, A: 1},
 		OnStartup:        func(ctx context.Context) { app.SetUIContext(ctx) },
 		Bind:             []interface{}{app},
+		OnShutdown: func(ctx context.Context) {
+			// Finalise any recording in progress so the file isn't left truncated
+			if err := app.Close(); err != nil {
+				log.Printf("shutdown: %v", err)
+			}
+		},
 	})
 	if err != nil {
 		_, _ = os.Stderr.WriteString(err.Error())